@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dubbie/calculator-api/internal/app/dataset"
+	"github.com/dubbie/calculator-api/internal/config"
+	"github.com/dubbie/calculator-api/internal/database"
+	"github.com/dubbie/calculator-api/internal/logging"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage/mysql"
+)
+
+// runExport implements the "export" subcommand and returns the process exit code:
+// 0 on success, 1 if the export failed, 2 on usage errors.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server export [-o file.json]")
+		flags.PrintDefaults()
+	}
+	outPath := flags.String("o", "-", "file to write the dataset to, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	logger, err := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up logging: %v\n", err)
+		return 1
+	}
+	db, err := database.NewDBConnection(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to establish database connection: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	exportService := service.NewExportService(mysql.NewMySQLExportStore(db))
+	ds, err := exportService.Export(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+
+	var output io.WriteCloser = os.Stdout
+	if *outPath != "-" {
+		if output, err = os.Create(*outPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+			return 1
+		}
+	}
+
+	err = dataset.EncodeJSON(output, ds)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write dataset: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d crafting methods, %d items and %d recipes\n",
+		len(ds.CraftingMethods), len(ds.Items), len(ds.Recipes))
+	return 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dubbie/calculator-api/internal/app/dataset"
+	"github.com/dubbie/calculator-api/internal/config"
+	"github.com/dubbie/calculator-api/internal/database"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/logging"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage/mysql"
+)
+
+// runImport implements the "import" subcommand and returns the process exit code:
+// 0 if the import was committed, 1 if it was rolled back or failed, 2 on usage errors.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server import [flags] <file.json|file.csv|->")
+		flags.PrintDefaults()
+	}
+	mode := flags.String("mode", string(domain.ImportAtomic), "atomic or skip_invalid")
+	format := flags.String("format", "", "json or csv (default: from the file extension)")
+	kind := flags.String("type", "", "kind of rows in a CSV file: crafting_methods, items or recipes (default: from the file name)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if *format == "csv" && *kind == "" {
+		*kind = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open dataset: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var (
+		ds  domain.Dataset
+		err error
+	)
+	switch *format {
+	case "json":
+		ds, err = dataset.DecodeJSON(input)
+	case "csv":
+		ds, err = dataset.DecodeCSV(input, *kind)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown dataset format %q, use -format json or -format csv\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read dataset: %v\n", err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	logger, err := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up logging: %v\n", err)
+		return 1
+	}
+	db, err := database.NewDBConnection(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to establish database connection: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	importService := service.NewImportService(mysql.NewMySQLImportStore(db))
+	report, err := importService.Import(context.Background(), service.ImportRequest{
+		Dataset: ds,
+		Mode:    domain.ImportMode(*mode),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		return 1
+	}
+
+	// The report goes to stdout so it can be piped; the summary goes to stderr
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+	}
+	fmt.Fprintf(os.Stderr, "Created %d, updated %d, failed %d rows; committed: %t\n",
+		report.Created, report.Updated, report.Failed, report.Committed)
+
+	if !report.Committed {
+		return 1
+	}
+	return 0
+}
@@ -3,54 +3,110 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/dubbie/calculator-api/internal/auth"
 	"github.com/dubbie/calculator-api/internal/config"
 	"github.com/dubbie/calculator-api/internal/database"
 	"github.com/dubbie/calculator-api/internal/domain"
 	"github.com/dubbie/calculator-api/internal/handler"
+	"github.com/dubbie/calculator-api/internal/logging"
+	"github.com/dubbie/calculator-api/internal/metrics"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
 	"github.com/dubbie/calculator-api/internal/service"
 	"github.com/dubbie/calculator-api/internal/storage/mysql"
 )
 
 func main() {
-	fmt.Println("Starting Crafting API server...")
+	// Subcommands run a single task against the database instead of serving the API
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
+	}
+
+	// Until the configuration is loaded, log through the default text logger
+	slog.Info("Starting Crafting API server")
 
 	// 1. Load Configuration
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
-		fmt.Printf("Failed to load configuration: %v\n", err)
-		return
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		slog.Error("Failed to set up logging", "error", err)
+		os.Exit(1)
 	}
-	fmt.Println("Configuration loaded.")
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", "log_format", cfg.LogFormat, "log_level", cfg.LogLevel)
 
 	// 2. Estabilish Database Connection
-	db, err := database.NewDBConnection(cfg)
+	db, err := database.NewDBConnection(cfg, logger)
 	if err != nil {
-		fmt.Printf("Failed to establish database connection: %v\n", err)
+		logger.Error("Failed to establish database connection", "error", err)
 		os.Exit(1)
 	}
-	fmt.Println("Database connection established.")
+
+	metricsRegistry := metrics.NewRegistry()
+	database.RegisterPoolMetrics(metricsRegistry, db)
+	queryMetrics := mysql.NewQueryMetrics(metricsRegistry)
 
 	// 3. Initialize Storage Layer
-	itemStore := mysql.NewMySQLItemStore(db)
-	craftingMethodStore := mysql.NewMySQLCraftingMethodStore(db)
+	itemStore := mysql.NewMySQLItemStore(db, queryMetrics)
+	craftingMethodStore := mysql.NewMySQLCraftingMethodStore(db, queryMetrics)
+	recipeStore := mysql.NewMySQLRecipeStore(db)
+	preferredRecipeStore := mysql.NewMySQLPreferredRecipeStore(db)
+	userStore := mysql.NewMySQLUserStore(db)
+	inventoryStore := mysql.NewMySQLInventoryStore(db)
+	projectStore := mysql.NewMySQLProjectStore(db)
+	importStore := mysql.NewMySQLImportStore(db)
+	exportStore := mysql.NewMySQLExportStore(db)
 
 	// 4. Initialze Service Layer
-	itemService := service.NewItemService(itemStore)
-	craftingMethodService := service.NewCraftingMethodService(craftingMethodStore)
+	itemService := service.NewItemService(itemStore, logger)
+	craftingMethodService := service.NewCraftingMethodService(craftingMethodStore, logger)
+	recipeService := service.NewRecipeService(recipeStore, recipegraph.NewChecker(recipeStore), logger)
+	calculatorService := service.NewCalculatorService(itemStore, recipeStore, preferredRecipeStore)
+	energyService := service.NewEnergyService(recipeStore)
+	preferredRecipeService := service.NewPreferredRecipeService(itemStore, recipeStore, preferredRecipeStore, logger)
+	userService := service.NewUserService(userStore, auth.NewTokenManager(cfg.AuthTokenSecret, cfg.AuthTokenTTL), logger)
+	inventoryService := service.NewInventoryService(inventoryStore)
+	projectService := service.NewProjectService(projectStore, itemStore, recipeStore, preferredRecipeStore, logger)
+	importService := service.NewImportService(importStore)
+	exportService := service.NewExportService(exportStore)
 	// Cast custom list services to the generic ListService interface for items
 	itemListService := itemService.(service.ListService[domain.Item, domain.ItemFilters])
 	craftingMethodListService := craftingMethodService.(service.ListService[domain.CraftingMethod, domain.CraftingMethodFilters])
+	recipeListService := recipeService.(service.ListService[domain.Recipe, domain.RecipeFilters])
+	inventoryListService := inventoryService.(service.ListService[domain.InventoryItem, domain.InventoryFilters])
+	projectListService := projectService.(service.ListService[domain.Project, domain.ProjectFilters])
 
 	// 5. Setup Router & Handlers
-	router := handler.SetupRoutes(cfg, itemService, itemListService, craftingMethodService, craftingMethodListService)
-	fmt.Println("Router setup complete.")
+	router := handler.SetupRoutes(
+		cfg,
+		logger,
+		metricsRegistry,
+		itemService, itemListService,
+		craftingMethodService, craftingMethodListService,
+		recipeService, recipeListService,
+		calculatorService, energyService,
+		preferredRecipeService,
+		userService,
+		inventoryService, inventoryListService,
+		projectService, projectListService,
+		importService, exportService,
+	)
+	logger.Info("Router setup complete")
 
 	// 6. Create and Configure HTTP Server
 	server := &http.Server{
@@ -60,17 +116,40 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
 
 	// 7. Start Server in a Goroutine
 	go func() {
-		fmt.Printf("Server listening on port %s\n", cfg.ServerPort)
+		logger.Info("Server listening", "port", cfg.ServerPort)
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			fmt.Printf("Error starting server: %v\n", err)
+			logger.Error("Error starting server", "error", err)
 			os.Exit(1)
 		}
 	}()
 
+	// Metrics on their own port keep scrapes apart from API traffic; they still need an admin key
+	var adminServer *http.Server
+	if cfg.MetricsPort != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", handler.MetricsHandler(cfg.APIKeys, metricsRegistry))
+		adminServer = &http.Server{
+			Addr:         ":" + cfg.MetricsPort,
+			Handler:      adminMux,
+			WriteTimeout: 15 * time.Second,
+			ReadTimeout:  15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+			ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		}
+		go func() {
+			logger.Info("Metrics server listening", "port", cfg.MetricsPort)
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Error starting metrics server", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// 8. Graceful Shutdown Handling
 	quit := make(chan os.Signal, 1)
 	// signal.Notify listens for specified signals (interrupt, terminate)
@@ -78,7 +157,7 @@ func main() {
 
 	// Block until a signal is received.
 	<-quit
-	fmt.Println("Shutdown signal received, initiating graceful shutdown...")
+	logger.Info("Shutdown signal received, initiating graceful shutdown")
 
 	// Create a context with a timeout for shutdown.
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // 30-second timeout
@@ -86,9 +165,14 @@ func main() {
 
 	// Attempt graceful shutdown
 	if err := server.Shutdown(ctx); err != nil {
-		fmt.Printf("Server forced to shutdown: %v\n", err)
+		logger.Error("Server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			logger.Error("Metrics server forced to shutdown", "error", err)
+		}
+	}
 
-	fmt.Println("Server exiting gracefully.")
+	logger.Info("Server exiting gracefully")
 }
@@ -0,0 +1,115 @@
+// Package binomial answers questions about the number of successes in a series of
+// independent trials that each succeed with the same probability.
+package binomial
+
+import "math"
+
+// maxTrials bounds the search in TrialsNeeded.
+const maxTrials = uint64(1) << 40
+
+// Mean returns the expected number of successes in n trials.
+func Mean(n uint64, p float64) float64 {
+	return float64(n) * p
+}
+
+// Variance returns the variance of the number of successes in n trials.
+func Variance(n uint64, p float64) float64 {
+	return float64(n) * p * (1 - p)
+}
+
+// CDF returns P(X <= k) for X ~ Binomial(n, p).
+func CDF(n uint64, p float64, k uint64) float64 {
+	if k >= n || p <= 0 {
+		return 1
+	}
+	if p >= 1 {
+		return 0
+	}
+
+	// Sum the probability mass in log space; terms too small to matter underflow to 0.
+	logP, logQ := math.Log(p), math.Log1p(-p)
+	lgN := lgamma(float64(n) + 1)
+	sum := 0.0
+	for i := tailStart(n, p); i <= k; i++ {
+		sum += math.Exp(lgN - lgamma(float64(i)+1) - lgamma(float64(n-i)+1) +
+			float64(i)*logP + float64(n-i)*logQ)
+	}
+	return math.Min(sum, 1)
+}
+
+// AtLeast returns the largest k such that P(X >= k) >= confidence for X ~ Binomial(n, p),
+// i.e. the number of successes that can be counted on with the given confidence.
+func AtLeast(n uint64, p, confidence float64) uint64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return n
+	}
+
+	// P(X >= k) >= c  <=>  P(X <= k-1) <= 1-c, so k is the first value whose CDF exceeds 1-c.
+	logP, logQ := math.Log(p), math.Log1p(-p)
+	lgN := lgamma(float64(n) + 1)
+	sum := 0.0
+	for k := tailStart(n, p); k < n; k++ {
+		sum += math.Exp(lgN - lgamma(float64(k)+1) - lgamma(float64(n-k)+1) +
+			float64(k)*logP + float64(n-k)*logQ)
+		if sum > 1-confidence {
+			return k
+		}
+	}
+	return n
+}
+
+// TrialsNeeded returns the smallest n such that n trials yield at least successes
+// successes with the given confidence. It returns false if no n up to 2^40 does.
+func TrialsNeeded(successes uint64, p, confidence float64) (uint64, bool) {
+	if successes == 0 {
+		return 0, true
+	}
+	if p <= 0 {
+		return 0, false
+	}
+	if p >= 1 {
+		return successes, true
+	}
+
+	reached := func(n uint64) bool {
+		return 1-CDF(n, p, successes-1) >= confidence
+	}
+
+	// Grow an upper bound from the expected number of trials, then bisect.
+	lo := successes
+	hi := max(uint64(float64(successes)/p), successes)
+	for !reached(hi) {
+		if hi >= maxTrials {
+			return 0, false
+		}
+		lo = hi + 1
+		hi = min(hi*2, maxTrials)
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if reached(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, true
+}
+
+// tailStart returns a number of successes below which the total probability mass is
+// negligible, so sums over the lower tail can skip the values before it.
+func tailStart(n uint64, p float64) uint64 {
+	margin := 40*math.Sqrt(Variance(n, p)) + 40
+	if start := Mean(n, p) - margin; start > 0 {
+		return uint64(start)
+	}
+	return 0
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
@@ -0,0 +1,136 @@
+package binomial
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCDF(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint64
+		p    float64
+		k    uint64
+		want float64
+	}{
+		{"no successes of two", 2, 0.5, 0, 0.25},
+		{"at most one of two", 2, 0.5, 1, 0.75},
+		{"k equals n", 2, 0.5, 2, 1},
+		{"k above n", 2, 0.5, 7, 1},
+		{"no trials", 0, 0.5, 0, 1},
+		{"impossible success", 10, 0, 0, 1},
+		{"certain success", 10, 1, 9, 0},
+		{"ten trials", 10, 0.3, 3, 0.6496107184},
+		{"skewed", 20, 0.05, 0, 0.3584859224},
+		{"large n, symmetric", 1_000_000, 0.5, 500_000, 0.5003989420},
+		{"large n, far tail", 1_000_000, 0.5, 400_000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CDF(tt.n, tt.p, tt.k); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CDF(%d, %v, %d) = %.10f, want %.10f", tt.n, tt.p, tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          uint64
+		p          float64
+		confidence float64
+		want       uint64
+	}{
+		{"likely one of two", 2, 0.5, 0.5, 1},
+		{"too confident for any", 2, 0.5, 0.9, 0},
+		{"both of two", 2, 0.5, 0.2, 2},
+		{"impossible success", 10, 0, 0.5, 0},
+		{"certain success", 10, 1, 0.99, 10},
+		{"median of a hundred", 100, 0.5, 0.5, 50},
+		{"confident of a hundred", 100, 0.5, 0.95, 42},
+		{"no trials", 0, 0.5, 0.5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AtLeast(tt.n, tt.p, tt.confidence)
+			if got != tt.want {
+				t.Errorf("AtLeast(%d, %v, %v) = %d, want %d", tt.n, tt.p, tt.confidence, got, tt.want)
+			}
+			// The answer is the last k that can still be counted on
+			if got > 0 && 1-CDF(tt.n, tt.p, got-1) < tt.confidence {
+				t.Errorf("P(X >= %d) = %v, below the confidence", got, 1-CDF(tt.n, tt.p, got-1))
+			}
+		})
+	}
+}
+
+func TestTrialsNeeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		successes  uint64
+		p          float64
+		confidence float64
+		want       uint64
+		wantOK     bool
+	}{
+		{"nothing needed", 0, 0.5, 0.99, 0, true},
+		{"never succeeds", 1, 0, 0.5, 0, false},
+		{"always succeeds", 5, 1, 0.99, 5, true},
+		{"one at a coin flip", 1, 0.5, 0.7, 2, true},
+		{"one, confidently", 1, 0.5, 0.9, 4, true},
+		{"one, very confidently", 1, 0.5, 0.99, 7, true},
+		{"one rare success", 1, 0.1, 0.95, 29, true},
+		{"many successes", 100, 0.25, 0.5, 399, true},
+		{"beyond the search bound", 1, 1e-12, 0.99, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TrialsNeeded(tt.successes, tt.p, tt.confidence)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("TrialsNeeded(%d, %v, %v) = %d, %v, want %d, %v", tt.successes, tt.p, tt.confidence, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTrialsNeededIsMinimal(t *testing.T) {
+	reached := func(n, successes uint64, p, confidence float64) bool {
+		return 1-CDF(n, p, successes-1) >= confidence
+	}
+	for _, successes := range []uint64{1, 3, 64, 1000} {
+		for _, p := range []float64{0.01, 0.3, 0.75, 0.999} {
+			for _, confidence := range []float64{0.5, 0.9, 0.999} {
+				n, ok := TrialsNeeded(successes, p, confidence)
+				if !ok {
+					t.Errorf("TrialsNeeded(%d, %v, %v) found no answer", successes, p, confidence)
+					continue
+				}
+				if !reached(n, successes, p, confidence) || (n > successes && reached(n-1, successes, p, confidence)) {
+					t.Errorf("TrialsNeeded(%d, %v, %v) = %d, not the fewest trials reaching the confidence", successes, p, confidence, n)
+				}
+			}
+		}
+	}
+}
+
+func TestMeanVariance(t *testing.T) {
+	tests := []struct {
+		n              uint64
+		p              float64
+		mean, variance float64
+	}{
+		{0, 0.5, 0, 0},
+		{10, 0.5, 5, 2.5},
+		{100, 0.1, 10, 9},
+		{8, 1, 8, 0},
+	}
+	for _, tt := range tests {
+		if got := Mean(tt.n, tt.p); math.Abs(got-tt.mean) > 1e-12 {
+			t.Errorf("Mean(%d, %v) = %v, want %v", tt.n, tt.p, got, tt.mean)
+		}
+		if got := Variance(tt.n, tt.p); math.Abs(got-tt.variance) > 1e-12 {
+			t.Errorf("Variance(%d, %v) = %v, want %v", tt.n, tt.p, got, tt.variance)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package dataset reads game data for bulk imports from JSON or CSV, and writes
+// exports as JSON.
+//
+// JSON input is a domain.Dataset, the same form EncodeJSON writes. CSV input holds one kind of row per file, with a
+// header naming the columns:
+//
+//	crafting_methods: slug, name, description
+//	items:            slug, name, is_raw_material, description, image_url
+//	recipes:          name, crafting_method, eu_per_tick, duration_ticks, notes, is_default, inputs, outputs
+//
+// Only name (and crafting_method for recipes) is required; other columns may be left
+// out and empty cells read as null. Recipe inputs are written as "slug:quantity"
+// pairs separated by ";", outputs as "slug:quantity[:chance]". The first output is
+// the primary one.
+package dataset
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// Kinds of CSV files.
+const (
+	KindCraftingMethods = "crafting_methods"
+	KindItems           = "items"
+	KindRecipes         = "recipes"
+)
+
+// ErrUnknownKind is returned for CSV kinds other than the Kind constants.
+var ErrUnknownKind = errors.New("unknown dataset kind")
+
+var columns = map[string][]string{
+	KindCraftingMethods: {"slug", "name", "description"},
+	KindItems:           {"slug", "name", "is_raw_material", "description", "image_url"},
+	KindRecipes:         {"name", "crafting_method", "eu_per_tick", "duration_ticks", "notes", "is_default", "inputs", "outputs"},
+}
+
+var requiredColumns = map[string][]string{
+	KindCraftingMethods: {"name"},
+	KindItems:           {"name"},
+	KindRecipes:         {"crafting_method"},
+}
+
+// DecodeJSON reads a dataset in its JSON form. Unknown fields are rejected so typos
+// don't silently drop data.
+func DecodeJSON(r io.Reader) (domain.Dataset, error) {
+	var ds domain.Dataset
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ds); err != nil {
+		return domain.Dataset{}, fmt.Errorf("invalid JSON dataset: %w", err)
+	}
+	return ds, nil
+}
+
+// EncodeJSON writes a dataset as indented JSON, one field per line, so that
+// version-controlled exports produce line-based diffs.
+func EncodeJSON(w io.Writer, ds *domain.Dataset) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ds); err != nil {
+		return fmt.Errorf("failed to encode dataset: %w", err)
+	}
+	return nil
+}
+
+// DecodeCSV reads rows of one kind from CSV into a dataset.
+func DecodeCSV(r io.Reader, kind string) (domain.Dataset, error) {
+	known, ok := columns[kind]
+	if !ok {
+		return domain.Dataset{}, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return domain.Dataset{}, errors.New("CSV dataset is empty")
+		}
+		return domain.Dataset{}, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	index, err := columnIndex(header, known, requiredColumns[kind])
+	if err != nil {
+		return domain.Dataset{}, err
+	}
+
+	var ds domain.Dataset
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return domain.Dataset{}, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := csvRow{record: record, index: index}
+
+		switch kind {
+		case KindCraftingMethods:
+			ds.CraftingMethods = append(ds.CraftingMethods, domain.DatasetCraftingMethod{
+				Slug:        row.get("slug"),
+				Name:        row.get("name"),
+				Description: row.nullString("description"),
+			})
+		case KindItems:
+			item := domain.DatasetItem{
+				Slug:        row.get("slug"),
+				Name:        row.get("name"),
+				Description: row.nullString("description"),
+				ImageURL:    row.nullString("image_url"),
+			}
+			if item.IsRawMaterial, err = row.bool("is_raw_material"); err != nil {
+				return domain.Dataset{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			ds.Items = append(ds.Items, item)
+		case KindRecipes:
+			recipe, err := row.recipe()
+			if err != nil {
+				return domain.Dataset{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			ds.Recipes = append(ds.Recipes, recipe)
+		}
+	}
+
+	return ds, nil
+}
+
+// columnIndex maps column names to their position, rejecting unknown and duplicate
+// columns and reporting missing required ones.
+func columnIndex(header, known, required []string) (map[string]int, error) {
+	allowed := make(map[string]bool, len(known))
+	for _, name := range known {
+		allowed[name] = true
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !allowed[name] {
+			return nil, fmt.Errorf("unknown CSV column %q, expected some of %s", name, strings.Join(known, ", "))
+		}
+		if _, dup := index[name]; dup {
+			return nil, fmt.Errorf("CSV column %q appears more than once", name)
+		}
+		index[name] = i
+	}
+
+	for _, name := range required {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("CSV column %q is required", name)
+		}
+	}
+
+	return index, nil
+}
+
+// csvRow reads the cells of one record by column name.
+type csvRow struct {
+	record []string
+	index  map[string]int
+}
+
+// get returns the trimmed cell, or "" if the column is absent.
+func (r csvRow) get(column string) string {
+	i, ok := r.index[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) nullString(column string) domain.JSONNullString {
+	value := r.get(column)
+	return domain.JSONNullString{NullString: sql.NullString{String: value, Valid: value != ""}}
+}
+
+func (r csvRow) nullInt64(column string) (domain.JSONNullInt64, error) {
+	value := r.get(column)
+	if value == "" {
+		return domain.JSONNullInt64{}, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return domain.JSONNullInt64{}, fmt.Errorf("%s must be an integer, got %q", column, value)
+	}
+	return domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: n, Valid: true}}, nil
+}
+
+func (r csvRow) bool(column string) (bool, error) {
+	value := r.get(column)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", column, value)
+	}
+	return b, nil
+}
+
+func (r csvRow) recipe() (domain.DatasetRecipe, error) {
+	recipe := domain.DatasetRecipe{
+		Name:           r.nullString("name"),
+		CraftingMethod: r.get("crafting_method"),
+		Notes:          r.nullString("notes"),
+	}
+
+	var err error
+	if recipe.EUPerTick, err = r.nullInt64("eu_per_tick"); err != nil {
+		return recipe, err
+	}
+	if recipe.DurationTicks, err = r.nullInt64("duration_ticks"); err != nil {
+		return recipe, err
+	}
+	if recipe.IsDefault, err = r.bool("is_default"); err != nil {
+		return recipe, err
+	}
+
+	for _, part := range splitList(r.get("inputs")) {
+		fields := strings.Split(part, ":")
+		if len(fields) != 2 {
+			return recipe, fmt.Errorf("input %q must be slug:quantity", part)
+		}
+		quantity, err := parseUint32(fields[1])
+		if err != nil {
+			return recipe, fmt.Errorf("input %q: quantity %w", part, err)
+		}
+		recipe.Inputs = append(recipe.Inputs, domain.DatasetRecipeInput{
+			Item:     strings.TrimSpace(fields[0]),
+			Quantity: quantity,
+		})
+	}
+
+	for i, part := range splitList(r.get("outputs")) {
+		fields := strings.Split(part, ":")
+		if len(fields) != 2 && len(fields) != 3 {
+			return recipe, fmt.Errorf("output %q must be slug:quantity or slug:quantity:chance", part)
+		}
+		output := domain.DatasetRecipeOutput{
+			Item:            strings.TrimSpace(fields[0]),
+			IsPrimaryOutput: i == 0,
+		}
+		if output.Quantity, err = parseUint32(fields[1]); err != nil {
+			return recipe, fmt.Errorf("output %q: quantity %w", part, err)
+		}
+		if len(fields) == 3 {
+			if output.Chance, err = parseUint32(fields[2]); err != nil {
+				return recipe, fmt.Errorf("output %q: chance %w", part, err)
+			}
+		}
+		recipe.Outputs = append(recipe.Outputs, output)
+	}
+
+	return recipe, nil
+}
+
+// splitList splits a ";" separated cell, dropping empty entries.
+func splitList(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func parseUint32(value string) (uint32, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("must be a non-negative integer, got %q", value)
+	}
+	return uint32(n), nil
+}
@@ -0,0 +1,218 @@
+package dataset
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+func nullString(s string) domain.JSONNullString {
+	return domain.JSONNullString{NullString: sql.NullString{String: s, Valid: true}}
+}
+
+func nullInt64(n int64) domain.JSONNullInt64 {
+	return domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: n, Valid: true}}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		csv  string
+		want domain.Dataset
+	}{
+		{
+			name: "crafting methods",
+			kind: KindCraftingMethods,
+			csv:  "slug,name,description\nmacerator,Macerator,Grinds ores\n,Furnace,\n",
+			want: domain.Dataset{CraftingMethods: []domain.DatasetCraftingMethod{
+				{Slug: "macerator", Name: "Macerator", Description: nullString("Grinds ores")},
+				{Name: "Furnace"},
+			}},
+		},
+		{
+			name: "only the required column",
+			kind: KindCraftingMethods,
+			csv:  "name\nFurnace\n",
+			want: domain.Dataset{CraftingMethods: []domain.DatasetCraftingMethod{{Name: "Furnace"}}},
+		},
+		{
+			name: "header in any case and order, cells trimmed",
+			kind: KindCraftingMethods,
+			csv:  "Name , SLUG\n  Furnace  ,furnace\n",
+			want: domain.Dataset{CraftingMethods: []domain.DatasetCraftingMethod{{Slug: "furnace", Name: "Furnace"}}},
+		},
+		{
+			name: "quoted cells",
+			kind: KindCraftingMethods,
+			csv:  "name,description\n\"Furnace, Electric\",\"Says \"\"hot\"\"\nand more\"\n",
+			want: domain.Dataset{CraftingMethods: []domain.DatasetCraftingMethod{
+				{Name: "Furnace, Electric", Description: nullString("Says \"hot\"\nand more")},
+			}},
+		},
+		{
+			name: "items",
+			kind: KindItems,
+			csv:  "name,is_raw_material,image_url,description\nIron Ore,true,https://example.com/ore.png,\nIron Plate,,,Flat\nCoal,0,,\n",
+			want: domain.Dataset{Items: []domain.DatasetItem{
+				{Name: "Iron Ore", IsRawMaterial: true, ImageURL: nullString("https://example.com/ore.png")},
+				{Name: "Iron Plate", Description: nullString("Flat")},
+				{Name: "Coal"},
+			}},
+		},
+		{
+			name: "recipes",
+			kind: KindRecipes,
+			csv: "name,crafting_method,eu_per_tick,duration_ticks,notes,is_default,inputs,outputs\n" +
+				"Iron Plate,furnace,32,200,Smelts,true,iron-ore:2; coal:1,iron-plate:1; slag:1:2500\n",
+			want: domain.Dataset{Recipes: []domain.DatasetRecipe{{
+				Name:           nullString("Iron Plate"),
+				CraftingMethod: "furnace",
+				EUPerTick:      nullInt64(32),
+				DurationTicks:  nullInt64(200),
+				Notes:          nullString("Smelts"),
+				IsDefault:      true,
+				Inputs: []domain.DatasetRecipeInput{
+					{Item: "iron-ore", Quantity: 2},
+					{Item: "coal", Quantity: 1},
+				},
+				Outputs: []domain.DatasetRecipeOutput{
+					{Item: "iron-plate", Quantity: 1, IsPrimaryOutput: true},
+					{Item: "slag", Quantity: 1, Chance: 2500},
+				},
+			}}},
+		},
+		{
+			name: "recipe lists with spaces and empty entries",
+			kind: KindRecipes,
+			csv:  "crafting_method,inputs,outputs\nfurnace, ; iron-ore : 2 ;; ,; iron-plate:1 ;\n",
+			want: domain.Dataset{Recipes: []domain.DatasetRecipe{{
+				CraftingMethod: "furnace",
+				Inputs:         []domain.DatasetRecipeInput{{Item: "iron-ore", Quantity: 2}},
+				Outputs:        []domain.DatasetRecipeOutput{{Item: "iron-plate", Quantity: 1, IsPrimaryOutput: true}},
+			}}},
+		},
+		{
+			name: "recipe with only a crafting method",
+			kind: KindRecipes,
+			csv:  "crafting_method\nfurnace\n",
+			want: domain.Dataset{Recipes: []domain.DatasetRecipe{{CraftingMethod: "furnace"}}},
+		},
+		{
+			name: "header only",
+			kind: KindItems,
+			csv:  "slug,name\n",
+			want: domain.Dataset{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCSV(strings.NewReader(tt.csv), tt.kind)
+			if err != nil {
+				t.Fatalf("DecodeCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCSV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		csv  string
+		want string
+	}{
+		{"empty", KindItems, "", "CSV dataset is empty"},
+		{"unknown column", KindItems, "name,colour\nOre,red\n", `unknown CSV column "colour"`},
+		{"column of another kind", KindCraftingMethods, "name,is_raw_material\nOre,true\n", `unknown CSV column "is_raw_material"`},
+		{"duplicate column", KindItems, "name,Name\nOre,Ore\n", `CSV column "name" appears more than once`},
+		{"missing name", KindItems, "slug\nore\n", `CSV column "name" is required`},
+		{"missing crafting method", KindRecipes, "name,inputs\nPlate,ore:1\n", `CSV column "crafting_method" is required`},
+		{"wrong number of cells", KindItems, "slug,name\nore\n", "invalid CSV"},
+		{"bare quote", KindItems, "name\nIron \"Ore\"\n", "invalid CSV"},
+		{"bad bool", KindItems, "name,is_raw_material\nOre,true\nPlate,maybe\n", `line 3: is_raw_material must be true or false, got "maybe"`},
+		{"bad integer", KindRecipes, "crafting_method,eu_per_tick\nfurnace,fast\n", `line 2: eu_per_tick must be an integer, got "fast"`},
+		{"bad duration", KindRecipes, "crafting_method,duration_ticks\nfurnace,1.5\n", "line 2: duration_ticks must be an integer"},
+		{"bad is_default", KindRecipes, "crafting_method,is_default\nfurnace,yes\n", "line 2: is_default must be true or false"},
+		{"input without quantity", KindRecipes, "crafting_method,inputs\nfurnace,iron-ore\n", `input "iron-ore" must be slug:quantity`},
+		{"input with a chance", KindRecipes, "crafting_method,inputs\nfurnace,iron-ore:1:5000\n", `input "iron-ore:1:5000" must be slug:quantity`},
+		{"negative input", KindRecipes, "crafting_method,inputs\nfurnace,iron-ore:-1\n", `input "iron-ore:-1": quantity must be a non-negative integer`},
+		{"input too large", KindRecipes, "crafting_method,inputs\nfurnace,iron-ore:4294967296\n", "quantity must be a non-negative integer"},
+		{"output without quantity", KindRecipes, "crafting_method,outputs\nfurnace,iron-plate\n", `output "iron-plate" must be slug:quantity or slug:quantity:chance`},
+		{"output with too many fields", KindRecipes, "crafting_method,outputs\nfurnace,iron-plate:1:2:3\n", "must be slug:quantity or slug:quantity:chance"},
+		{"bad output quantity", KindRecipes, "crafting_method,outputs\nfurnace,iron-plate:many\n", `output "iron-plate:many": quantity must be`},
+		{"bad output chance", KindRecipes, "crafting_method,outputs\nfurnace,slag:1:half\n", `output "slag:1:half": chance must be`},
+		{"error on a later line", KindRecipes, "crafting_method,inputs\nfurnace,ore:1\n\"furnace\",ore:x\n", "line 3:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCSV(strings.NewReader(tt.csv), tt.kind)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeCSV() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVUnknownKind(t *testing.T) {
+	_, err := DecodeCSV(strings.NewReader("name\nOre\n"), "machines")
+	if !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("DecodeCSV() error = %v, want ErrUnknownKind", err)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	ds := domain.Dataset{
+		CraftingMethods: []domain.DatasetCraftingMethod{{Slug: "furnace", Name: "Furnace"}},
+		Items: []domain.DatasetItem{
+			{Slug: "iron-ore", Name: "Iron Ore", IsRawMaterial: true, Description: nullString("Rock")},
+		},
+		Recipes: []domain.DatasetRecipe{{
+			Name:           nullString("Iron Plate"),
+			CraftingMethod: "furnace",
+			DurationTicks:  nullInt64(200),
+			Inputs:         []domain.DatasetRecipeInput{{Item: "iron-ore", Quantity: 1}},
+			Outputs:        []domain.DatasetRecipeOutput{{Item: "iron-plate", Quantity: 1, Chance: domain.ChanceGuaranteed, IsPrimaryOutput: true}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, &ds); err != nil {
+		t.Fatalf("EncodeJSON() error = %v", err)
+	}
+	got, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, ds) {
+		t.Errorf("DecodeJSON(EncodeJSON()) = %+v, want %+v", got, ds)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"empty", ""},
+		{"not JSON", "items: []"},
+		{"unknown field", `{"items": [{"name": "Ore", "colour": "red"}]}`},
+		{"misspelled section", `{"itemz": []}`},
+		{"wrong type", `{"items": [{"name": 1}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeJSON(strings.NewReader(tt.json)); err == nil {
+				t.Errorf("DecodeJSON(%q) error = nil, want an error", tt.json)
+			}
+		})
+	}
+}
@@ -2,9 +2,15 @@ package pagination
 
 import (
 	// Import errors
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/schema"
 )
@@ -15,10 +21,14 @@ const (
 	MaxPerPage     = 100
 )
 
+// ErrInvalidCursor is returned for cursors that are malformed or don't fit the request.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type BaseListParams struct {
 	Page    int    `schema:"page"`
 	PerPage int    `schema:"per_page"`
 	Sort    string `schema:"sort"`
+	Cursor  string `schema:"cursor"`
 }
 
 // ListParams embeds BaseListParams and adds specific filters.
@@ -27,19 +37,192 @@ type ListParams[F any] struct {
 	PerPage int
 	Sort    string
 
+	// CursorMode selects keyset pagination: Page is ignored and no total is counted.
+	// Cursor is the position to continue from, or nil for the first page.
+	CursorMode bool
+	Cursor     *Cursor
+
+	// Total is the number of rows matched by the filters when the caller already counted
+	// them, e.g. for the ListState, so that listing a page does not count them again.
+	Total *int64
+
 	// Filters inside
 	Filters F
 }
 
 // PaginatedResponse defines the standard structure for paginated list responses.
+// The page URLs are filled in by WithURLs; next and prev are nil at either end of the list.
 type PaginatedResponse[T any] struct {
-	Total       int64 `json:"total"`
-	PerPage     int   `json:"per_page"`
-	CurrentPage int   `json:"current_page"`
-	LastPage    int   `json:"last_page"`
-	From        int   `json:"from"`
-	To          int   `json:"to"`
-	Data        []T   `json:"data"`
+	Total        int64   `json:"total"`
+	PerPage      int     `json:"per_page"`
+	CurrentPage  int     `json:"current_page"`
+	LastPage     int     `json:"last_page"`
+	From         int     `json:"from"`
+	To           int     `json:"to"`
+	FirstPageURL string  `json:"first_page_url"`
+	NextPageURL  *string `json:"next_page_url"`
+	PrevPageURL  *string `json:"prev_page_url"`
+	LastPageURL  string  `json:"last_page_url"`
+	Data         []T     `json:"data"`
+}
+
+// WithURLs returns the response with its page URLs pointing at requestURL with the page
+// number replaced, keeping the filters, sort and per_page of the request.
+func (p PaginatedResponse[T]) WithURLs(requestURL *url.URL) PaginatedResponse[T] {
+	pageURL := func(page int) string {
+		return withQueryParam(requestURL, "page", strconv.Itoa(page))
+	}
+
+	lastPage := max(p.LastPage, 1)
+	p.FirstPageURL = pageURL(1)
+	p.LastPageURL = pageURL(lastPage)
+	p.NextPageURL, p.PrevPageURL = nil, nil
+	if p.CurrentPage < lastPage {
+		next := pageURL(p.CurrentPage + 1)
+		p.NextPageURL = &next
+	}
+	if p.CurrentPage > 1 {
+		prev := pageURL(min(p.CurrentPage-1, lastPage))
+		p.PrevPageURL = &prev
+	}
+	return p
+}
+
+// Link returns the RFC 8288 Link header value for the page URLs set by WithURLs.
+func (p PaginatedResponse[T]) Link() string {
+	return formatLink([]linkRelation{
+		{"first", &p.FirstPageURL},
+		{"prev", p.PrevPageURL},
+		{"next", p.NextPageURL},
+		{"last", &p.LastPageURL},
+	})
+}
+
+// CursorResponse is the response of a list in cursor mode. The cursors are nil at
+// either end of the list.
+type CursorResponse[T any] struct {
+	PerPage     int     `json:"per_page"`
+	NextCursor  *string `json:"next_cursor"`
+	PrevCursor  *string `json:"prev_cursor"`
+	NextPageURL *string `json:"next_page_url"`
+	PrevPageURL *string `json:"prev_page_url"`
+	Data        []T     `json:"data"`
+}
+
+// WithURLs returns the response with its page URLs pointing at requestURL with the
+// cursor replaced and the page number, which cursor mode ignores, removed.
+func (c CursorResponse[T]) WithURLs(requestURL *url.URL) CursorResponse[T] {
+	pageURL := func(cursor *string) *string {
+		if cursor == nil {
+			return nil
+		}
+		u := withQueryParam(requestURL, "cursor", *cursor, "page")
+		return &u
+	}
+
+	c.NextPageURL = pageURL(c.NextCursor)
+	c.PrevPageURL = pageURL(c.PrevCursor)
+	return c
+}
+
+// Link returns the RFC 8288 Link header value for the page URLs set by WithURLs.
+func (c CursorResponse[T]) Link() string {
+	return formatLink([]linkRelation{
+		{"prev", c.PrevPageURL},
+		{"next", c.NextPageURL},
+	})
+}
+
+// linkRelation is one target of a Link header; it is left out when url is nil.
+type linkRelation struct {
+	rel string
+	url *string
+}
+
+func formatLink(relations []linkRelation) string {
+	links := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		if relation.url != nil {
+			links = append(links, fmt.Sprintf("<%s>; rel=%q", *relation.url, relation.rel))
+		}
+	}
+	return strings.Join(links, ", ")
+}
+
+// withQueryParam returns the path and query of u with one query parameter replaced and
+// the unused ones removed. The result is relative to the host the request was sent to,
+// so it stays correct behind proxies that rewrite the scheme or host.
+func withQueryParam(u *url.URL, key, value string, unused ...string) string {
+	query := u.Query()
+	for _, name := range unused {
+		query.Del(name)
+	}
+	query.Set(key, value)
+	return (&url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: query.Encode()}).String()
+}
+
+// ListState summarizes all rows matched by a list's filters and what they embed. Any insert,
+// update or delete among them changes the state as a whole, which makes it a cheap validator
+// for cached list responses. LastModified alone is not one: deleting a row other than the
+// newest leaves it unchanged.
+type ListState struct {
+	Count        int64
+	LastModified time.Time // Newest updated_at of the rows, zero for an empty list
+	Revision     uint64    // Sum of the row versions, which tells apart writes within a second
+}
+
+// CursorPage holds the cursors around one page of a list in cursor mode.
+type CursorPage struct {
+	Next *string
+	Prev *string
+}
+
+// NewCursorResponse creates a CursorResponse instance.
+func NewCursorResponse[T any](data []T, page CursorPage, perPage int) CursorResponse[T] {
+	return CursorResponse[T]{
+		PerPage:    perPage,
+		NextCursor: page.Next,
+		PrevCursor: page.Prev,
+		Data:       data,
+	}
+}
+
+// Cursor is a position between two rows of a list ordered by a sort key and then by ID.
+// It continues after the row with Key and ID, or before it when Backward is set.
+type Cursor struct {
+	Sort     string          `json:"s"`
+	Key      json.RawMessage `json:"k"`
+	ID       uint64          `json:"i"`
+	Backward bool            `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // Marshalling a struct of plain fields cannot fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Key) == 0 {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+	return &cursor, nil
+}
+
+// ParseSort splits a sort parameter such as "created_at_desc" into its field and direction.
+// The direction defaults to descending unless it is "asc"; ok is false without a field.
+func ParseSort(sort string) (field string, desc bool, ok bool) {
+	idx := strings.LastIndex(sort, "_")
+	if idx <= 0 {
+		return "", false, false
+	}
+	return sort[:idx], strings.ToLower(sort[idx+1:]) != "asc", true
 }
 
 // NewPaginatedResponse creates a PaginatedResponse instance.
@@ -133,5 +316,23 @@ func ParseListParams[F any](queryParams url.Values) (ListParams[F], error) {
 		Filters: filters,
 	}
 
+	// --- Cursor mode ---
+	// Any cursor parameter opts in, an empty one requests the first page
+	if _, ok := queryParams["cursor"]; ok {
+		finalParams.CursorMode = true
+		if baseParams.Cursor != "" {
+			cursor, err := DecodeCursor(baseParams.Cursor)
+			if err != nil {
+				return ListParams[F]{}, err
+			}
+			// The cursor remembers its sort so follow-up requests don't have to repeat it
+			if baseParams.Sort != "" && baseParams.Sort != cursor.Sort {
+				return ListParams[F]{}, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidCursor, cursor.Sort)
+			}
+			finalParams.Sort = cursor.Sort
+			finalParams.Cursor = cursor
+		}
+	}
+
 	return finalParams, nil
 }
@@ -0,0 +1,181 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"string key", Cursor{Sort: "name_asc", Key: json.RawMessage(`"Iron Plate"`), ID: 7}},
+		{"number key", Cursor{Sort: "id_desc", Key: json.RawMessage(`42`), ID: 42}},
+		{"time key", Cursor{Sort: "created_at_desc", Key: json.RawMessage(`"2025-01-01T12:00:00Z"`), ID: 3}},
+		{"backward", Cursor{Sort: "name_asc", Key: json.RawMessage(`"a"`), ID: 1, Backward: true}},
+		{"default sort", Cursor{Key: json.RawMessage(`"a"`), ID: 1}},
+		{"key needing escapes", Cursor{Sort: "name_asc", Key: json.RawMessage(`"a/b?c=d&e \"f\" ü"`), ID: 18446744073709551615}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.cursor.Encode()
+			if escaped := url.QueryEscape(encoded); escaped != encoded {
+				t.Errorf("Encode() = %q, which is not URL safe", encoded)
+			}
+
+			got, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q) error = %v", encoded, err)
+			}
+			// Keys round-trip as JSON values; Encode may escape characters such as &
+			var gotKey, wantKey any
+			if err := json.Unmarshal(got.Key, &gotKey); err != nil {
+				t.Fatalf("cursor key %s is not JSON: %v", got.Key, err)
+			}
+			_ = json.Unmarshal(tt.cursor.Key, &wantKey)
+			if !reflect.DeepEqual(gotKey, wantKey) {
+				t.Errorf("DecodeCursor(Encode()) key = %s, want %s", got.Key, tt.cursor.Key)
+			}
+			got.Key, tt.cursor.Key = nil, nil
+			if !reflect.DeepEqual(*got, tt.cursor) {
+				t.Errorf("DecodeCursor(Encode()) = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"name_asc","k":"a","i":12}`))},
+		{"standard alphabet", base64.StdEncoding.EncodeToString([]byte(`{"s":"a?","k":"a>>","i":1}`))},
+		{"not JSON", encode("name_asc:a:1")},
+		{"JSON array", encode(`["name_asc","a",1]`)},
+		{"missing key", encode(`{"s":"name_asc","i":1}`)},
+		{"negative ID", encode(`{"s":"name_asc","k":"a","i":-1}`)},
+		{"ID not a number", encode(`{"s":"name_asc","k":"a","i":"1"}`)},
+		{"truncated", Cursor{Sort: "name_asc", Key: json.RawMessage(`"a"`), ID: 1}.Encode()[:10]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.encoded)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %+v, %v, want ErrInvalidCursor", tt.encoded, cursor, err)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort      string
+		wantField string
+		wantDesc  bool
+		wantOK    bool
+	}{
+		{"name_asc", "name", false, true},
+		{"name_desc", "name", true, true},
+		{"name_ASC", "name", false, true},
+		{"created_at_desc", "created_at", true, true},
+		{"created_at_asc", "created_at", false, true},
+		{"name_sideways", "name", true, true},
+		{"name_", "name", true, true},
+		{"name", "", false, false},
+		{"_asc", "", false, false},
+		{"", "", false, false},
+	}
+	for _, tt := range tests {
+		field, desc, ok := ParseSort(tt.sort)
+		if field != tt.wantField || desc != tt.wantDesc || ok != tt.wantOK {
+			t.Errorf("ParseSort(%q) = %q, %v, %v, want %q, %v, %v", tt.sort, field, desc, ok, tt.wantField, tt.wantDesc, tt.wantOK)
+		}
+	}
+}
+
+type testFilters struct {
+	Name string `schema:"name"`
+}
+
+func TestParseListParamsCursor(t *testing.T) {
+	nameCursor := Cursor{Sort: "name_asc", Key: json.RawMessage(`"m"`), ID: 5}
+	defaultCursor := Cursor{Key: json.RawMessage(`5`), ID: 5, Backward: true}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantMode   bool
+		wantSort   string
+		wantCursor *Cursor
+		wantErr    bool
+	}{
+		{"offset mode", "page=2&sort=name_asc", false, "name_asc", nil, false},
+		{"empty cursor starts cursor mode", "cursor=&sort=name_asc", true, "name_asc", nil, false},
+		{"cursor carries its sort", "cursor=" + nameCursor.Encode(), true, "name_asc", &nameCursor, false},
+		{"cursor with its sort repeated", "cursor=" + nameCursor.Encode() + "&sort=name_asc", true, "name_asc", &nameCursor, false},
+		{"cursor of the default sort", "cursor=" + defaultCursor.Encode(), true, "", &defaultCursor, false},
+		{"cursor with another sort", "cursor=" + nameCursor.Encode() + "&sort=name_desc", false, "", nil, true},
+		{"malformed cursor", "cursor=not-a-cursor", false, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query + "&name=iron")
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			params, err := ParseListParams[testFilters](query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("ParseListParams() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseListParams() error = %v", err)
+			}
+			if params.CursorMode != tt.wantMode || params.Sort != tt.wantSort || !reflect.DeepEqual(params.Cursor, tt.wantCursor) {
+				t.Errorf("ParseListParams() = mode %v, sort %q, cursor %+v, want %v, %q, %+v",
+					params.CursorMode, params.Sort, params.Cursor, tt.wantMode, tt.wantSort, tt.wantCursor)
+			}
+			if params.Filters.Name != "iron" {
+				t.Errorf("ParseListParams() filters = %+v, want name iron", params.Filters)
+			}
+		})
+	}
+}
+
+func TestParseListParamsPaging(t *testing.T) {
+	tests := []struct {
+		query       string
+		wantPage    int
+		wantPerPage int
+	}{
+		{"", DefaultPage, DefaultPerPage},
+		{"page=3&per_page=20", 3, 20},
+		{"page=0&per_page=0", DefaultPage, DefaultPerPage},
+		{"page=-2&per_page=-5", DefaultPage, DefaultPerPage},
+		{"per_page=1000", DefaultPage, MaxPerPage},
+	}
+	for _, tt := range tests {
+		query, _ := url.ParseQuery(tt.query)
+		params, err := ParseListParams[testFilters](query)
+		if err != nil {
+			t.Errorf("ParseListParams(%q) error = %v", tt.query, err)
+			continue
+		}
+		if params.Page != tt.wantPage || params.PerPage != tt.wantPerPage {
+			t.Errorf("ParseListParams(%q) = page %d, per_page %d, want %d, %d", tt.query, params.Page, params.PerPage, tt.wantPage, tt.wantPerPage)
+		}
+	}
+}
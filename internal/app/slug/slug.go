@@ -0,0 +1,120 @@
+// Package slug derives URL-safe identifiers from display names.
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength matches the VARCHAR(255) slug columns.
+const MaxLength = 255
+
+// maxSuffixLength is the longest "-N" suffix Unique can append.
+const maxSuffixLength = len("-4294967295")
+
+var validRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// transliterations spells out letters that do not decompose into a base letter and accents.
+var transliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'ø': "o",
+	'đ': "d",
+	'ð': "d",
+	'þ': "th",
+	'ł': "l",
+	'ı': "i",
+	'ħ': "h",
+}
+
+// Make lowercases name, transliterates accented letters to ASCII ("Façade" becomes "facade")
+// and joins the words with dashes. Other characters are dropped, so the result may be empty.
+func Make(name string) string {
+	var b strings.Builder
+	pendingDash := false
+	writeLetter := func(r rune) {
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteRune(r)
+	}
+
+	for _, r := range norm.NFKD.String(strings.ToLower(name)) {
+		r = unicode.ToLower(r)
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Accents split off by the decomposition
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+			writeLetter(r)
+		case transliterations[r] != "":
+			for _, t := range transliterations[r] {
+				writeLetter(t)
+			}
+		case unicode.IsSpace(r), r == '-', r == '_':
+			pendingDash = true
+		}
+	}
+	return truncate(b.String(), MaxLength)
+}
+
+// Valid reports whether s is a usable slug.
+func Valid(s string) bool {
+	return len(s) <= MaxLength && validRegex.MatchString(s)
+}
+
+// WithSuffix returns base with "-n" appended, shortening base to keep the result within
+// MaxLength. The first variant of a slug is base itself, so n <= 1 returns base unchanged.
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(base, MaxLength-len(suffix)) + suffix
+}
+
+// IsVariant reports whether s is base or base with a suffix added by WithSuffix.
+func IsVariant(s, base string) bool {
+	if s == base {
+		return true
+	}
+	i := strings.LastIndexByte(s, '-')
+	if i < 0 {
+		return false
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	return err == nil && n > 1 && s[i+1] != '0' && WithSuffix(base, n) == s
+}
+
+// SearchPrefix returns a prefix shared by base and all of its variants, for finding the
+// variants already in use.
+func SearchPrefix(base string) string {
+	return truncate(base, MaxLength-maxSuffixLength)
+}
+
+// Unique returns the first variant of base that is not in taken: base itself, then
+// base-2, base-3 and so on.
+func Unique(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+	for n := 1; ; n++ {
+		if candidate := WithSuffix(base, n); !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// truncate cuts s to at most n bytes without leaving a trailing dash. Slugs are ASCII.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		s = s[:n]
+	}
+	return strings.TrimRight(s, "-")
+}
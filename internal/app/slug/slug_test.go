@@ -0,0 +1,181 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"words", "Iron Plate", "iron-plate"},
+		{"surrounding and repeated spaces", "  Iron \t Plate  ", "iron-plate"},
+		{"dashes and underscores", "a--b__c_-d", "a-b-c-d"},
+		{"punctuation dropped", "Circuit (Tier 2)", "circuit-tier-2"},
+		{"punctuation joins", "Mk.II", "mkii"},
+		{"already a slug", "iron-plate-2", "iron-plate-2"},
+		{"accents", "Crème brûlée", "creme-brulee"},
+		{"cedilla", "Façade", "facade"},
+		{"sharp s", "Straße", "strasse"},
+		{"ligatures and slashed letters", "Ærø Œuvre", "aero-oeuvre"},
+		{"stroke", "Łódź", "lodz"},
+		{"dotted capital I", "İstanbul", "istanbul"},
+		{"compatibility forms", "ﬁle x²", "file-x2"},
+		{"full width", "ＡＢＣ１", "abc1"},
+		{"no latin letters", "日本語", ""},
+		{"greek", "ΑΒΓ", ""},
+		{"only separators", " - _ ", ""},
+		{"empty", "", ""},
+		{"long", strings.Repeat("a", 300), strings.Repeat("a", MaxLength)},
+		{"long, cut at a dash", strings.Repeat("ab ", 100), strings.TrimSuffix(strings.Repeat("ab-", 85), "-")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Make(tt.in)
+			if got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if got != "" && !Valid(got) {
+				t.Errorf("Make(%q) = %q, which is not Valid", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"iron-plate", true},
+		{"iron-plate-2", true},
+		{"123", true},
+		{strings.Repeat("a", MaxLength), true},
+		{strings.Repeat("a", MaxLength+1), false},
+		{"", false},
+		{"Iron", false},
+		{"iron plate", false},
+		{"iron_plate", false},
+		{"façade", false},
+		{"iron/plate", false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.in); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	long := strings.Repeat("a", MaxLength)
+	dashed := strings.Repeat("a", 252) + "-bc"
+
+	tests := []struct {
+		name string
+		base string
+		n    int
+		want string
+	}{
+		{"first variant", "iron", 1, "iron"},
+		{"zero", "iron", 0, "iron"},
+		{"negative", "iron", -3, "iron"},
+		{"second variant", "iron", 2, "iron-2"},
+		{"large", "iron", 4294967295, "iron-4294967295"},
+		{"shortens a long base", long, 2, strings.Repeat("a", MaxLength-2) + "-2"},
+		{"no double dash after shortening", dashed, 2, strings.Repeat("a", 252) + "-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithSuffix(tt.base, tt.n)
+			if got != tt.want {
+				t.Errorf("WithSuffix(%q, %d) = %q, want %q", tt.base, tt.n, got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("WithSuffix(%q, %d) = %q, which is not Valid", tt.base, tt.n, got)
+			}
+		})
+	}
+}
+
+func TestIsVariant(t *testing.T) {
+	long := strings.Repeat("a", MaxLength)
+
+	tests := []struct {
+		s, base string
+		want    bool
+	}{
+		{"iron", "iron", true},
+		{"iron-2", "iron", true},
+		{"iron-37", "iron", true},
+		{"iron-2-3", "iron-2", true},
+		{WithSuffix(long, 12), long, true},
+		{"iron-1", "iron", false},
+		{"iron-0", "iron", false},
+		{"iron-02", "iron", false},
+		{"iron-+2", "iron", false},
+		{"iron-", "iron", false},
+		{"iron-x", "iron", false},
+		{"iron-plate", "iron", false},
+		{"iron-3", "iron-2", false},
+		{"iron", "iron-2", false},
+		{"ironing-2", "iron", false},
+		{"other", "iron", false},
+	}
+	for _, tt := range tests {
+		if got := IsVariant(tt.s, tt.base); got != tt.want {
+			t.Errorf("IsVariant(%q, %q) = %v, want %v", tt.s, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestSearchPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{"short", "iron-plate", "iron-plate"},
+		{"long", strings.Repeat("a", MaxLength), strings.Repeat("a", MaxLength-maxSuffixLength)},
+		{"long, cut at a dash", strings.Repeat("a", MaxLength-maxSuffixLength-1) + "-" + strings.Repeat("b", 20), strings.Repeat("a", MaxLength-maxSuffixLength-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SearchPrefix(tt.base)
+			if got != tt.want {
+				t.Errorf("SearchPrefix(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+			for _, n := range []int{1, 2, 10, 4294967295} {
+				if variant := WithSuffix(tt.base, n); !strings.HasPrefix(variant, got) {
+					t.Errorf("variant %q does not start with the search prefix %q", variant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		taken []string
+		want  string
+	}{
+		{"nothing taken", "iron", nil, "iron"},
+		{"unrelated taken", "iron", []string{"gold", "iron-plate"}, "iron"},
+		{"base taken", "iron", []string{"iron"}, "iron-2"},
+		{"several taken", "iron", []string{"iron-3", "iron", "iron-2"}, "iron-4"},
+		{"fills a gap", "iron", []string{"iron", "iron-3"}, "iron-2"},
+		{"only a variant taken", "iron", []string{"iron-2"}, "iron"},
+		{"long base", strings.Repeat("a", MaxLength), []string{strings.Repeat("a", MaxLength)}, strings.Repeat("a", MaxLength-2) + "-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unique(tt.base, tt.taken); got != tt.want {
+				t.Errorf("Unique(%q, %v) = %q, want %q", tt.base, tt.taken, got, tt.want)
+			}
+		})
+	}
+}
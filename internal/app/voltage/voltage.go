@@ -0,0 +1,41 @@
+// Package voltage classifies energy usage into GregTech-style voltage tiers.
+package voltage
+
+// Tier is a voltage tier and the highest EU/t a single amp of it supplies.
+type Tier struct {
+	Name         string
+	MaxEUPerTick int64
+}
+
+// Tiers lists every tier from lowest to highest; each supplies four times the previous one.
+var Tiers = []Tier{
+	{"ULV", 8},
+	{"LV", 32},
+	{"MV", 128},
+	{"HV", 512},
+	{"EV", 2048},
+	{"IV", 8192},
+	{"LuV", 32768},
+	{"ZPM", 131072},
+	{"UV", 524288},
+	{"UHV", 2097152},
+	{"UEV", 8388608},
+	{"UIV", 33554432},
+	{"UXV", 134217728},
+	{"OpV", 536870912},
+	{"MAX", 2147483647},
+}
+
+// Classify returns the lowest tier able to run a recipe drawing euPerTick.
+// It returns false for recipes that draw no power or more than the highest tier supplies.
+func Classify(euPerTick int64) (Tier, bool) {
+	if euPerTick <= 0 {
+		return Tier{}, false
+	}
+	for _, tier := range Tiers {
+		if euPerTick <= tier.MaxEUPerTick {
+			return tier, true
+		}
+	}
+	return Tier{}, false
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+type contextKey int
+
+const (
+	userContextKey contextKey = iota
+	roleContextKey
+)
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user, if any.
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*domain.User)
+	return user, ok && user != nil
+}
+
+// WithRole returns a copy of ctx carrying the role granted by the request's API key.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleContextKey, role)
+}
+
+// RoleFromContext returns the role granted by the request's API key, if any.
+func RoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleContextKey).(Role)
+	return role, ok
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// Role is the access level granted to an API key. Higher roles include the lower ones.
+type Role int
+
+const (
+	RoleViewer Role = iota + 1
+	RoleEditor
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleViewer: "viewer",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// AtLeast reports whether r grants everything min does.
+func (r Role) AtLeast(min Role) bool {
+	return r >= min
+}
+
+// ParseRole parses a role name, ignoring case.
+func ParseRole(name string) (Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown role %q, expected viewer, editor or admin", name)
+}
+
+// APIKeys maps static API keys to the role they grant.
+type APIKeys map[string]Role
+
+// ParseAPIKeys parses a comma-separated list of "key:role" pairs.
+func ParseAPIKeys(s string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// Split on the last colon; role names never contain one, keys might.
+		sep := strings.LastIndex(entry, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid API key entry, expected key:role")
+		}
+		key := strings.TrimSpace(entry[:sep])
+		if key == "" {
+			return nil, fmt.Errorf("invalid API key entry, expected key:role")
+		}
+		role, err := ParseRole(entry[sep+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key entry for key ending in %q: %w", keySuffix(key), err)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("API key ending in %q is configured more than once", keySuffix(key))
+		}
+		keys[key] = role
+	}
+	return keys, nil
+}
+
+// Lookup returns the role granted to key. Every configured key is compared in
+// constant time so response timing doesn't reveal partial matches.
+func (k APIKeys) Lookup(key string) (Role, bool) {
+	var found Role
+	for candidate, role := range k {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			found = role
+		}
+	}
+	return found, found != 0
+}
+
+// keySuffix returns the last few characters of a key, enough to identify it in errors.
+func keySuffix(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+	return key[len(key)-4:]
+}
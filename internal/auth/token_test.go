@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestTokenManager(secret string) *TokenManager {
+	m := NewTokenManager(secret, time.Hour)
+	m.now = func() time.Time { return testNow }
+	return m
+}
+
+// forgeToken builds a token from raw header and payload JSON, signed like Issue signs.
+func forgeToken(m *TokenManager, header, payload string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return unsigned + "." + m.sign(unsigned)
+}
+
+func TestTokenManagerIssueVerify(t *testing.T) {
+	m := newTestTokenManager("secret")
+
+	token, expiresAt, err := m.Issue(42)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if want := testNow.Add(time.Hour); !expiresAt.Equal(want) {
+		t.Errorf("Issue() expiresAt = %v, want %v", expiresAt, want)
+	}
+
+	userID, err := m.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if userID != 42 {
+		t.Errorf("Verify() = %d, want 42", userID)
+	}
+}
+
+func TestTokenManagerVerifyRejects(t *testing.T) {
+	m := newTestTokenManager("secret")
+	valid, _, err := m.Issue(42)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	parts := strings.Split(valid, ".")
+	otherSecret, _, _ := newTestTokenManager("other secret").Issue(42)
+
+	const hs256 = `{"alg":"HS256","typ":"JWT"}`
+	validClaims := `{"sub":"42","iat":1735732800,"exp":1735736400}`
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"empty", "", ErrInvalidToken},
+		{"one segment", parts[0], ErrInvalidToken},
+		{"two segments", parts[0] + "." + parts[1], ErrInvalidToken},
+		{"four segments", valid + "." + parts[2], ErrInvalidToken},
+		{"empty signature", parts[0] + "." + parts[1] + ".", ErrInvalidToken},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2])), ErrInvalidToken},
+		{"signed with another secret", otherSecret, ErrInvalidToken},
+		{"tampered payload", parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","exp":9999999999}`)) + "." + parts[2], ErrInvalidToken},
+		{"alg none", forgeToken(m, `{"alg":"none","typ":"JWT"}`, validClaims), ErrInvalidToken},
+		{"alg none unsigned", base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + ".", ErrInvalidToken},
+		{"alg HS512", forgeToken(m, `{"alg":"HS512","typ":"JWT"}`, validClaims), ErrInvalidToken},
+		{"alg RS256", forgeToken(m, `{"alg":"RS256","typ":"JWT"}`, validClaims), ErrInvalidToken},
+		{"header with extra fields", forgeToken(m, `{"alg":"HS256","typ":"JWT","kid":"x"}`, validClaims), ErrInvalidToken},
+		{"payload not base64", forgeTokenRaw(m, parts[0], "!!!"), ErrInvalidToken},
+		{"payload not JSON", forgeToken(m, hs256, `not json`), ErrInvalidToken},
+		{"subject missing", forgeToken(m, hs256, `{"exp":1735736400}`), ErrInvalidToken},
+		{"subject zero", forgeToken(m, hs256, `{"sub":"0","exp":1735736400}`), ErrInvalidToken},
+		{"subject not a number", forgeToken(m, hs256, `{"sub":"admin","exp":1735736400}`), ErrInvalidToken},
+		{"expired", forgeToken(m, hs256, `{"sub":"42","exp":1735732799}`), ErrTokenExpired},
+		{"expiring now", forgeToken(m, hs256, `{"sub":"42","exp":1735732800}`), ErrTokenExpired},
+		{"no expiry", forgeToken(m, hs256, `{"sub":"42"}`), ErrTokenExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := m.Verify(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %d, %v, want error %v", userID, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenManagerVerifyExpiresAfterTTL(t *testing.T) {
+	m := newTestTokenManager("secret")
+	token, _, err := m.Issue(7)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    error
+	}{
+		{"fresh", 0, nil},
+		{"just before expiry", time.Hour - time.Second, nil},
+		{"at expiry", time.Hour, ErrTokenExpired},
+		{"long after expiry", 48 * time.Hour, ErrTokenExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.now = func() time.Time { return testNow.Add(tt.elapsed) }
+			if _, err := m.Verify(token); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// forgeTokenRaw signs already encoded header and payload segments.
+func forgeTokenRaw(m *TokenManager, header, payload string) string {
+	unsigned := header + "." + payload
+	return unsigned + "." + m.sign(unsigned)
+}
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/dubbie/calculator-api/internal/auth"
 	"github.com/spf13/viper"
 )
 
@@ -15,6 +20,20 @@ type Config struct {
 	DBPassword     string   `mapstructure:"DB_PASSWORD"`
 	DBName         string   `mapstructure:"DB_NAME"`
 	AllowedOrigins []string `mapstructure:"ALLOWED_ORIGINS"`
+
+	// LogFormat is "text" or "json"; LogLevel is "debug", "info", "warn" or "error".
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
+
+	// MetricsPort serves /metrics on a separate admin server. When empty, /metrics is
+	// served by the API server instead. Either way it requires an admin API key.
+	MetricsPort string `mapstructure:"METRICS_PORT"`
+
+	AuthTokenSecret string        `mapstructure:"AUTH_TOKEN_SECRET"`
+	AuthTokenTTL    time.Duration `mapstructure:"AUTH_TOKEN_TTL"`
+
+	// APIKeys is parsed from API_KEYS, a comma-separated list of "key:role" pairs.
+	APIKeys auth.APIKeys `mapstructure:"-"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -27,13 +46,19 @@ func LoadConfig(path string) (config Config, err error) {
 	// Set defaults
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("ALLOWED_ORIGINS", "http://localhost:5173,http://127.0.01:5173")
+	viper.SetDefault("AUTH_TOKEN_SECRET", "")
+	viper.SetDefault("AUTH_TOKEN_TTL", "24h")
+	viper.SetDefault("API_KEYS", "")
+	viper.SetDefault("LOG_FORMAT", "text")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("METRICS_PORT", "")
 
 	err = viper.ReadInConfig()
 	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return Config{}, fmt.Errorf("error reading config file: %w", err)
 		}
-		fmt.Println("Config file (.env) not found, using defaults and environment variables.")
+		slog.Info("Config file (.env) not found, using defaults and environment variables")
 		err = nil
 	}
 
@@ -59,5 +84,26 @@ func LoadConfig(path string) (config Config, err error) {
 	}
 	config.AllowedOrigins = cleanedOrigins
 
+	config.APIKeys, err = auth.ParseAPIKeys(viper.GetString("API_KEYS"))
+	if err != nil {
+		return Config{}, fmt.Errorf("error parsing API_KEYS: %w", err)
+	}
+	if len(config.APIKeys) == 0 {
+		slog.Warn("API_KEYS is not set, write endpoints will reject every request")
+	}
+
+	// Without a configured secret, tokens can't outlive the process.
+	if config.AuthTokenSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return Config{}, fmt.Errorf("error generating auth token secret: %w", err)
+		}
+		config.AuthTokenSecret = hex.EncodeToString(secret)
+		slog.Warn("AUTH_TOKEN_SECRET is not set, using a random secret. Issued tokens will stop working on restart")
+	}
+	if config.AuthTokenTTL <= 0 {
+		return Config{}, fmt.Errorf("AUTH_TOKEN_TTL must be a positive duration, got %s", config.AuthTokenTTL)
+	}
+
 	return
 }
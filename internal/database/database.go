@@ -1,15 +1,18 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/dubbie/calculator-api/internal/config"
+	"github.com/dubbie/calculator-api/internal/metrics"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
-func NewDBConnection(cfg config.Config) (*sqlx.DB, error) {
+func NewDBConnection(cfg config.Config, logger *slog.Logger) (*sqlx.DB, error) {
 	mysqlConfig := mysql.NewConfig()
 
 	mysqlConfig.Net = "tcp"
@@ -39,7 +42,36 @@ func NewDBConnection(cfg config.Config) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	fmt.Println("Database connection succesful!")
+	logger.Info("Database connection successful", "addr", mysqlConfig.Addr, "database", cfg.DBName)
 
 	return db, nil
 }
+
+// RegisterPoolMetrics exposes the connection pool statistics of db, read on every scrape.
+func RegisterPoolMetrics(registry *metrics.Registry, db *sqlx.DB) {
+	gauge := func(name, help string, value func(s sql.DBStats) float64) {
+		registry.NewGaugeFunc(name, help, func() float64 { return value(db.Stats()) })
+	}
+	counter := func(name, help string, value func(s sql.DBStats) float64) {
+		registry.NewCounterFunc(name, help, func() float64 { return value(db.Stats()) })
+	}
+
+	gauge("db_pool_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("db_pool_open_connections", "Number of established connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("db_pool_in_use_connections", "Number of connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("db_pool_idle_connections", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("db_pool_wait_count_total", "Number of times a query waited for a free connection.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("db_pool_wait_duration_seconds_total", "Total time spent waiting for a free connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("db_pool_max_idle_closed_total", "Connections closed because the idle pool was full.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("db_pool_max_idle_time_closed_total", "Connections closed for being idle too long.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("db_pool_max_lifetime_closed_total", "Connections closed for reaching their maximum lifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
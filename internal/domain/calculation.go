@@ -0,0 +1,43 @@
+package domain
+
+// ItemQuantity pairs an item with an amount of it.
+type ItemQuantity struct {
+	ItemID   uint64 `json:"item_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Quantity uint64 `json:"quantity"`
+}
+
+// RecipeRuns records how many times a recipe has to be run.
+type RecipeRuns struct {
+	RecipeID         uint64         `json:"recipe_id"`
+	RecipeName       JSONNullString `json:"recipe_name"`
+	CraftingMethodID uint64         `json:"crafting_method_id"`
+	OutputItemID     uint64         `json:"output_item_id"` // The item the recipe was selected for
+	Runs             uint64         `json:"runs"`
+}
+
+// CalculationResult is the full bill of materials for crafting a quantity of an item.
+type CalculationResult struct {
+	ItemID   uint64 `json:"item_id"`
+	Quantity uint64 `json:"quantity"`
+
+	// RawMaterials are the items flagged is_raw_material that have to be gathered.
+	RawMaterials []ItemQuantity `json:"raw_materials"`
+	// Intermediates are the crafted items consumed on the way to the target.
+	Intermediates []ItemQuantity `json:"intermediates"`
+	// Crafts lists every recipe that has to be run and how often.
+	Crafts []RecipeRuns `json:"crafts"`
+	// Leftovers are the surplus outputs and guaranteed byproducts remaining after all crafts.
+	Leftovers []ItemQuantity `json:"leftovers"`
+	// MissingRecipes are non-raw items no recipe produces; they're treated like raw materials.
+	MissingRecipes []ItemQuantity `json:"missing_recipes"`
+}
+
+// ShoppingList is a bill of materials after subtracting what the player already owns.
+// The embedded result only lists what still has to be crafted or gathered.
+type ShoppingList struct {
+	CalculationResult
+	// FromStock lists what is taken from the player's stock.
+	FromStock []ItemQuantity `json:"from_stock"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+// CraftableRecipe is a recipe an inventory can run and how many times.
+type CraftableRecipe struct {
+	Recipe Recipe `json:"recipe"`
+	// MaxRuns is how often the recipe can be run if nothing else uses its inputs.
+	MaxRuns uint64 `json:"max_runs"`
+	// Depth is 0 for recipes runnable from the inventory itself and n for recipes
+	// that need the outputs of n levels of intermediate crafts.
+	Depth uint `json:"depth"`
+}
+
+// CraftableRecipes lists every recipe an inventory can run, ordered by depth and then recipe ID.
+type CraftableRecipes struct {
+	Depth   uint              `json:"depth"` // Levels of intermediate crafts considered
+	Recipes []CraftableRecipe `json:"recipes"`
+}
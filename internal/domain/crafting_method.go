@@ -8,6 +8,7 @@ type CraftingMethod struct {
 	Name        string         `db:"name" json:"name"`
 	Slug        string         `db:"slug" json:"slug"`
 	Description JSONNullString `db:"description" json:"description"`
+	Version     uint64         `db:"version" json:"-"` // Incremented by every update, sent as the ETag
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,53 @@
+package domain
+
+// Dataset is the game data in a portable form: rows reference each other by slug
+// (or, for recipes, by name) instead of by database ID.
+type Dataset struct {
+	CraftingMethods []DatasetCraftingMethod `json:"crafting_methods"`
+	Items           []DatasetItem           `json:"items"`
+	Recipes         []DatasetRecipe         `json:"recipes"`
+}
+
+// DatasetCraftingMethod is a crafting method identified by its slug.
+type DatasetCraftingMethod struct {
+	Slug        string         `json:"slug"` // Derived from the name when empty
+	Name        string         `json:"name"`
+	Description JSONNullString `json:"description"`
+}
+
+// DatasetItem is an item identified by its slug.
+type DatasetItem struct {
+	Slug          string         `json:"slug"` // Derived from the name when empty
+	Name          string         `json:"name"`
+	IsRawMaterial bool           `json:"is_raw_material"`
+	Description   JSONNullString `json:"description"`
+	ImageURL      JSONNullString `json:"image_url"`
+}
+
+// DatasetRecipe is a recipe referencing its crafting method and items by slug.
+// Named recipes are identified by their name; unnamed ones can't be matched to stored recipes.
+type DatasetRecipe struct {
+	Name           JSONNullString        `json:"name"`
+	CraftingMethod string                `json:"crafting_method"`
+	EUPerTick      JSONNullInt64         `json:"eu_per_tick"`
+	DurationTicks  JSONNullInt64         `json:"duration_ticks"`
+	Notes          JSONNullString        `json:"notes"`
+	IsDefault      bool                  `json:"is_default"`
+	Inputs         []DatasetRecipeInput  `json:"inputs"`
+	Outputs        []DatasetRecipeOutput `json:"outputs"`
+}
+
+// DatasetRecipeInput is an item consumed by a dataset recipe.
+type DatasetRecipeInput struct {
+	Item     string `json:"item"`
+	Quantity uint32 `json:"quantity"`
+}
+
+// DatasetRecipeOutput is an item produced by a dataset recipe.
+// Zero quantity and chance fall back to 1 and ChanceGuaranteed.
+type DatasetRecipeOutput struct {
+	Item            string `json:"item"`
+	Quantity        uint32 `json:"quantity"`
+	Chance          uint32 `json:"chance"`
+	IsPrimaryOutput bool   `json:"is_primary_output"`
+}
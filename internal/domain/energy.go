@@ -0,0 +1,45 @@
+package domain
+
+// TicksPerSecond is the game's fixed tick rate.
+const TicksPerSecond = 20
+
+// RecipeEnergy is the energy and time used by running one recipe a number of times.
+type RecipeEnergy struct {
+	RecipeID         uint64         `json:"recipe_id"`
+	RecipeName       JSONNullString `json:"recipe_name"`
+	CraftingMethodID uint64         `json:"crafting_method_id"`
+	Runs             uint64         `json:"runs"`
+	EUPerTick        JSONNullInt64  `json:"eu_per_tick"`
+	DurationTicks    JSONNullInt64  `json:"duration_ticks"`
+	// VoltageTier is the lowest tier that can power the recipe, null if it draws no power.
+	VoltageTier  JSONNullString `json:"voltage_tier"`
+	TotalEU      uint64         `json:"total_eu"`
+	TotalTicks   uint64         `json:"total_ticks"`
+	TotalSeconds float64        `json:"total_seconds"`
+}
+
+// CraftingMethodEnergy totals the recipes of one crafting method.
+type CraftingMethodEnergy struct {
+	CraftingMethodID uint64  `json:"crafting_method_id"`
+	Runs             uint64  `json:"runs"`
+	TotalEU          uint64  `json:"total_eu"`
+	TotalTicks       uint64  `json:"total_ticks"`
+	TotalSeconds     float64 `json:"total_seconds"`
+	PeakEUPerTick    int64   `json:"peak_eu_per_tick"`
+	// VoltageTier is the tier needed for the method's most demanding recipe.
+	VoltageTier JSONNullString `json:"voltage_tier"`
+}
+
+// EnergyReport totals the energy and time of a crafting plan, assuming its runs happen one after another.
+type EnergyReport struct {
+	TotalEU       uint64         `json:"total_eu"`
+	TotalTicks    uint64         `json:"total_ticks"`
+	TotalSeconds  float64        `json:"total_seconds"`
+	PeakEUPerTick int64          `json:"peak_eu_per_tick"`
+	VoltageTier   JSONNullString `json:"voltage_tier"`
+
+	Recipes          []RecipeEnergy         `json:"recipes"`
+	ByCraftingMethod []CraftingMethodEnergy `json:"by_crafting_method"`
+	// MissingEnergyData lists recipes without eu_per_tick or duration_ticks; they count as zero.
+	MissingEnergyData []uint64 `json:"missing_energy_data"`
+}
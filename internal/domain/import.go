@@ -0,0 +1,48 @@
+package domain
+
+// ImportMode selects what happens to an import when some of its rows fail.
+type ImportMode string
+
+const (
+	// ImportAtomic commits the import only if every row succeeds.
+	ImportAtomic ImportMode = "atomic"
+	// ImportSkipInvalid commits the rows that succeed and reports the rest as failed.
+	ImportSkipInvalid ImportMode = "skip_invalid"
+)
+
+// ImportRowStatus is the outcome of importing one row.
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowUpdated ImportRowStatus = "updated"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// Kinds of rows in an import.
+const (
+	ImportKindCraftingMethod = "crafting_method"
+	ImportKindItem           = "item"
+	ImportKindRecipe         = "recipe"
+)
+
+// ImportRowResult reports what happened to one row of an import.
+type ImportRowResult struct {
+	Kind   string          `json:"kind"`
+	Row    int             `json:"row"` // 1-based position among the rows of its kind
+	Key    string          `json:"key"` // Slug, or recipe name if it has one
+	Status ImportRowStatus `json:"status"`
+	ID     uint64          `json:"id,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// ImportReport summarizes a bulk import. Rows reported as created or updated are
+// only persisted when Committed is set.
+type ImportReport struct {
+	Mode      ImportMode        `json:"mode"`
+	Committed bool              `json:"committed"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// InventoryItem is the quantity of an item a user owns.
+type InventoryItem struct {
+	ID        uint64    `db:"id" json:"id"`
+	UserID    uint64    `db:"user_id" json:"user_id"`
+	ItemID    uint64    `db:"item_id" json:"item_id"`
+	ItemName  string    `db:"item_name" json:"item_name"`
+	ItemSlug  string    `db:"item_slug" json:"item_slug"`
+	Quantity  uint64    `db:"quantity" json:"quantity"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// InventoryDelta changes the quantity of an item by a signed amount.
+type InventoryDelta struct {
+	ItemID uint64
+	Delta  int64
+}
+
+// InventoryFilters define parameters for listing a user's inventory.
+type InventoryFilters struct {
+	UserID   uint64  `schema:"-"` // Taken from the route, never from the query
+	ItemName *string `schema:"item_name"`
+}
@@ -12,6 +12,7 @@ type Item struct {
 	IsRawMaterial bool           `db:"is_raw_material" json:"is_raw_material"`
 	Description   JSONNullString `db:"description" json:"description"`
 	ImageURL      JSONNullString `db:"image_url" json:"image_url"`
+	Version       uint64         `db:"version" json:"-"` // Incremented by every update, sent as the ETag
 	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
 }
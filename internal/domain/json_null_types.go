@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 // JSONNullString wraps sql.NullString to customize JSON marshaling.
@@ -42,3 +43,69 @@ func (ns *JSONNullString) UnmarshalJSON(data []byte) error {
 	ns.Valid = true
 	return nil
 }
+
+// JSONNullInt64 wraps sql.NullInt64 to customize JSON marshaling.
+type JSONNullInt64 struct {
+	sql.NullInt64
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It marshals the Int64 value if Valid is true, otherwise marshals null.
+func (ni JSONNullInt64) MarshalJSON() ([]byte, error) {
+	if !ni.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ni.Int64)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It unmarshals the JSON value into the Int64 field if it's a valid integer, otherwise sets Valid to false.
+func (ni *JSONNullInt64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		ni.Valid = false
+		ni.Int64 = 0
+		return nil
+	}
+
+	var num int64
+	if err := json.Unmarshal(data, &num); err != nil {
+		return errors.New("JSONNullInt64: value must be an integer or null")
+	}
+
+	ni.Int64 = num
+	ni.Valid = true
+	return nil
+}
+
+// JSONNullTime wraps sql.NullTime to customize JSON marshaling.
+type JSONNullTime struct {
+	sql.NullTime
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It marshals the Time value if Valid is true, otherwise marshals null.
+func (nt JSONNullTime) MarshalJSON() ([]byte, error) {
+	if !nt.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(nt.Time)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It unmarshals an RFC 3339 timestamp into the Time field, otherwise sets Valid to false.
+func (nt *JSONNullTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		nt.Valid = false
+		nt.Time = time.Time{}
+		return nil
+	}
+
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return errors.New("JSONNullTime: value must be an RFC 3339 timestamp or null")
+	}
+
+	nt.Time = t
+	nt.Valid = true
+	return nil
+}
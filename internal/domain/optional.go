@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Optional is a field of a JSON Merge Patch (RFC 7396) document, which tells apart a
+// missing field (leave unchanged), null (clear) and a value (set).
+type Optional[T any] struct {
+	Value T
+	Set   bool // The field was present, possibly as null
+	Null  bool // The field was present as null
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It is only called for fields present in the document, which is what marks them as set.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	o.Set = true
+	o.Null = string(data) == "null"
+	// Nullable types such as JSONNullString decode null themselves; for others it leaves the zero value
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = value
+	return nil
+}
+
+// Or returns the value the field sets, or current if it is absent.
+func (o Optional[T]) Or(current T) T {
+	if o.Set {
+		return o.Value
+	}
+	return current
+}
+
+// ValidationValue returns a pointer to the value to validate, or nil when the field is absent
+// or null. With "omitempty" this skips fields left out while still checking a value being
+// set, even an empty one. Nullable SQL wrappers are unwrapped.
+func (o Optional[T]) ValidationValue() any {
+	if !o.Set || o.Null {
+		return nil
+	}
+	if valuer, ok := any(o.Value).(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil || value == nil {
+			return nil
+		}
+		return &value
+	}
+	return &o.Value
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// UserPreferredRecipe records which recipe a user wants used to craft an item.
+type UserPreferredRecipe struct {
+	ID                uint64    `db:"id" json:"id"`
+	UserID            uint64    `db:"user_id" json:"user_id"`
+	OutputItemID      uint64    `db:"output_item_id" json:"output_item_id"`
+	PreferredRecipeID uint64    `db:"preferred_recipe_id" json:"preferred_recipe_id"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Rules used to pick the recipe for an item, in order of precedence.
+const (
+	RecipeRulePreferred = "preferred" // The user's preferred recipe for the item
+	RecipeRuleDefault   = "default"   // A recipe flagged is_default
+	RecipeRuleFallback  = "fallback"  // Primary-output recipes first, then lowest ID
+)
+
+// ResolvedRecipe is the recipe chosen to craft an item and the rule that chose it.
+type ResolvedRecipe struct {
+	ItemID uint64  `json:"item_id"`
+	UserID *uint64 `json:"user_id"`
+	Rule   string  `json:"rule"`
+	Recipe *Recipe `json:"recipe"`
+}
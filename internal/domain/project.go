@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Project is a saved set of target items a user is working towards.
+type Project struct {
+	ID          uint64         `db:"id" json:"id"`
+	UserID      uint64         `db:"user_id" json:"user_id"`
+	Name        string         `db:"name" json:"name"`
+	Description JSONNullString `db:"description" json:"description"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+
+	Items []ProjectItem `db:"-" json:"items"`
+}
+
+// ProjectItem is a target of a project and how much of it has been produced so far.
+type ProjectItem struct {
+	ID                uint64    `db:"id" json:"id"`
+	ProjectID         uint64    `db:"project_id" json:"project_id"`
+	ItemID            uint64    `db:"item_id" json:"item_id"`
+	ItemName          string    `db:"item_name" json:"item_name"`
+	ItemSlug          string    `db:"item_slug" json:"item_slug"`
+	TargetQuantity    uint64    `db:"target_quantity" json:"target_quantity"`
+	ProducedQuantity  uint64    `db:"produced_quantity" json:"produced_quantity"`
+	RemainingQuantity uint64    `db:"remaining_quantity" json:"remaining_quantity"` // Computed, never below zero
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ProjectFilters define parameters for listing projects.
+type ProjectFilters struct {
+	UserID uint64  `schema:"-"` // The authenticated user, never taken from the query
+	Name   *string `schema:"name"`
+}
+
+// ProjectRequirements is what is still needed to finish a project's remaining targets.
+type ProjectRequirements struct {
+	ProjectID uint64 `json:"project_id"`
+	// Remaining lists each target's quantity still to be produced.
+	Remaining []ItemQuantity `json:"remaining"`
+
+	RawMaterials   []ItemQuantity `json:"raw_materials"`
+	Intermediates  []ItemQuantity `json:"intermediates"`
+	Crafts         []RecipeRuns   `json:"crafts"`
+	Leftovers      []ItemQuantity `json:"leftovers"`
+	MissingRecipes []ItemQuantity `json:"missing_recipes"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// ChanceGuaranteed is the recipe output chance (in basis points) that represents 100%.
+const ChanceGuaranteed = 10000
+
+// Recipe represents a way of turning input items into output items using a crafting method.
+type Recipe struct {
+	ID               uint64         `db:"id" json:"id"`
+	Name             JSONNullString `db:"name" json:"name"`
+	CraftingMethodID uint64         `db:"crafting_method_id" json:"crafting_method_id"`
+	EUPerTick        JSONNullInt64  `db:"eu_per_tick" json:"eu_per_tick"`
+	DurationTicks    JSONNullInt64  `db:"duration_ticks" json:"duration_ticks"`
+	Notes            JSONNullString `db:"notes" json:"notes"`
+	IsDefault        bool           `db:"is_default" json:"is_default"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+
+	// Inputs and Outputs are stored in their own tables and loaded separately.
+	Inputs  []RecipeInput  `db:"-" json:"inputs"`
+	Outputs []RecipeOutput `db:"-" json:"outputs"`
+}
+
+// RecipeInput represents an item consumed by a recipe.
+type RecipeInput struct {
+	ID            uint64 `db:"id" json:"id"`
+	RecipeID      uint64 `db:"recipe_id" json:"recipe_id"`
+	InputItemID   uint64 `db:"input_item_id" json:"input_item_id"`
+	InputQuantity uint32 `db:"input_quantity" json:"input_quantity"`
+}
+
+// RecipeOutput represents an item produced by a recipe.
+type RecipeOutput struct {
+	ID              uint64 `db:"id" json:"id"`
+	RecipeID        uint64 `db:"recipe_id" json:"recipe_id"`
+	ItemID          uint64 `db:"item_id" json:"item_id"`
+	Quantity        uint32 `db:"quantity" json:"quantity"`
+	Chance          uint32 `db:"chance" json:"chance"` // Basis points, 10000 = 100%
+	IsPrimaryOutput bool   `db:"is_primary_output" json:"is_primary_output"`
+}
+
+// RecipeFilters define parameters for listing recipes.
+type RecipeFilters struct {
+	Name             *string `schema:"name"` // Pointer allows checking if filter was provided
+	CraftingMethodID *uint64 `schema:"crafting_method_id"`
+	IsDefault        *bool   `schema:"is_default"`
+	InputItemID      *uint64 `schema:"input_item_id"`  // Recipes consuming this item
+	OutputItemID     *uint64 `schema:"output_item_id"` // Recipes producing this item
+}
+
+// RecipeEdge links an item a recipe produces to an item it consumes.
+// Together the edges of all recipes form the item dependency graph.
+type RecipeEdge struct {
+	RecipeID     uint64 `db:"recipe_id" json:"recipe_id"`
+	OutputItemID uint64 `db:"output_item_id" json:"output_item_id"`
+	InputItemID  uint64 `db:"input_item_id" json:"input_item_id"`
+}
+
+// RecipeCycle is a closed path through the item dependency graph.
+// ItemIDs starts and ends with the same item; RecipeIDs[i] links ItemIDs[i] to ItemIDs[i+1].
+type RecipeCycle struct {
+	ItemIDs   []uint64 `json:"item_ids"`
+	RecipeIDs []uint64 `json:"recipe_ids"`
+}
+
+// RecipeCycleReport lists every cycle found in the recipe graph.
+type RecipeCycleReport struct {
+	// Components are the groups of items that can all be reached from one another.
+	Components [][]uint64    `json:"components"`
+	Cycles     []RecipeCycle `json:"cycles"`
+	// Truncated is set when there were more cycles than the report limit.
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+// TicksPerMinute is how many game ticks pass in a minute.
+const TicksPerMinute = TicksPerSecond * 60
+
+// ItemRate pairs an item with a flow rate in items per minute.
+type ItemRate struct {
+	ItemID    uint64  `json:"item_id"`
+	Name      string  `json:"name"`
+	Slug      string  `json:"slug"`
+	PerMinute float64 `json:"per_minute"`
+}
+
+// ThroughputStep is one recipe of a production line running continuously.
+type ThroughputStep struct {
+	RecipeID         uint64         `json:"recipe_id"`
+	RecipeName       JSONNullString `json:"recipe_name"`
+	CraftingMethodID uint64         `json:"crafting_method_id"`
+	OutputItemID     uint64         `json:"output_item_id"` // The item the recipe was selected for
+	DurationTicks    JSONNullInt64  `json:"duration_ticks"`
+	RunsPerMinute    float64        `json:"runs_per_minute"`
+	// Machines is the exact number of machines needed in parallel; MachinesNeeded rounds it up.
+	// Both are null when the recipe has no duration_ticks.
+	Machines       *float64   `json:"machines"`
+	MachinesNeeded *uint64    `json:"machines_needed"`
+	Inputs         []ItemRate `json:"inputs"`
+	Outputs        []ItemRate `json:"outputs"`
+}
+
+// CraftingMethodThroughput totals the machines and item flows of one crafting method.
+type CraftingMethodThroughput struct {
+	CraftingMethodID uint64     `json:"crafting_method_id"`
+	Machines         float64    `json:"machines"`
+	MachinesNeeded   uint64     `json:"machines_needed"` // Sum of the rounded-up machines of each step
+	InputRates       []ItemRate `json:"input_rates"`
+	OutputRates      []ItemRate `json:"output_rates"`
+}
+
+// ThroughputPlan is a production line sustaining an output rate of an item.
+type ThroughputPlan struct {
+	ItemID         uint64  `json:"item_id"`
+	ItemsPerMinute float64 `json:"items_per_minute"`
+
+	Steps            []ThroughputStep           `json:"steps"`
+	ByCraftingMethod []CraftingMethodThroughput `json:"by_crafting_method"`
+	// RawMaterials are the items flagged is_raw_material that have to be supplied.
+	RawMaterials []ItemRate `json:"raw_materials"`
+	// Byproducts are the secondary outputs of the line at their expected rates.
+	Byproducts []ItemRate `json:"byproducts"`
+	// MissingRecipes are non-raw items no recipe produces, or whose recipe never yields them;
+	// they have to be supplied like raw materials.
+	MissingRecipes []ItemRate `json:"missing_recipes"`
+}
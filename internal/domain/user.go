@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// User represents an account that can log in to the API.
+type User struct {
+	ID            uint64         `db:"id" json:"id"`
+	Name          string         `db:"name" json:"name"`
+	Email         string         `db:"email" json:"email"`
+	Password      string         `db:"password" json:"-"` // bcrypt hash, never serialized
+	RememberToken JSONNullString `db:"remember_token" json:"-"`
+	CreatedAt     JSONNullTime   `db:"created_at" json:"created_at"`
+	UpdatedAt     JSONNullTime   `db:"updated_at" json:"updated_at"`
+}
+
+// AuthToken is a signed bearer token issued on login.
+type AuthToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	User        *User     `json:"user"`
+}
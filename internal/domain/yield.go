@@ -0,0 +1,36 @@
+package domain
+
+// OutputYield describes how much of one recipe output a number of runs produces.
+// Each run produces the output's full quantity with probability Chance/10000, otherwise nothing.
+type OutputYield struct {
+	ItemID          uint64  `json:"item_id"`
+	QuantityPerRun  uint32  `json:"quantity_per_run"`
+	Chance          uint32  `json:"chance"`
+	Probability     float64 `json:"probability"`
+	IsPrimaryOutput bool    `json:"is_primary_output"`
+
+	ExpectedQuantity  float64 `json:"expected_quantity"`
+	Variance          float64 `json:"variance"`
+	StandardDeviation float64 `json:"standard_deviation"`
+	MaxQuantity       uint64  `json:"max_quantity"`
+	// QuantityAtConfidence is the quantity produced at least with the requested confidence.
+	QuantityAtConfidence uint64 `json:"quantity_at_confidence"`
+}
+
+// YieldTarget reports how many runs are needed to collect a quantity of one output.
+type YieldTarget struct {
+	ItemID       uint64  `json:"item_id"`
+	Quantity     uint64  `json:"quantity"`
+	ExpectedRuns float64 `json:"expected_runs"`
+	// RunsNeeded is null when the target can't be reached with the requested confidence in a sane number of runs.
+	RunsNeeded *uint64 `json:"runs_needed"`
+}
+
+// RecipeYield is the output distribution of running a recipe a number of times.
+type RecipeYield struct {
+	RecipeID   uint64        `json:"recipe_id"`
+	Runs       uint64        `json:"runs"`
+	Confidence float64       `json:"confidence"`
+	Outputs    []OutputYield `json:"outputs"`
+	Target     *YieldTarget  `json:"target,omitempty"`
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type AuthHandler struct {
+	userService service.UserService
+}
+
+// NewAuthHandler creates a handler for registration, login and the current user.
+func NewAuthHandler(userService service.UserService) *AuthHandler {
+	return &AuthHandler{
+		userService: userService,
+	}
+}
+
+// RegisterAuthRoutes sets up the registration and login routes on the provided router.
+func (h *AuthHandler) RegisterAuthRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodPost, "/register", h.Register)
+	r.MethodFunc(http.MethodPost, "/login", h.Login)
+}
+
+// --- Register ---
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.RegisterRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	newUser, err := h.userService.Register(ctx, req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Email is already registered", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to register user", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newUser); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- Login ---
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.LoginRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	token, err := h.userService.Login(ctx, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			respondWithError(w, r, http.StatusUnauthorized, "Invalid email or password", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to log in", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(token); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- Me ---
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorized(w, r, "Authentication required", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// Authenticate resolves an "Authorization: Bearer <token>" header to a user and stores it
+// in the request context. Requests without the header pass through anonymously; a header
+// that is malformed or carries an invalid token is rejected with 401.
+func Authenticate(userService service.UserService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, found := strings.Cut(header, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				respondUnauthorized(w, r, "Malformed Authorization header, expected a Bearer token", nil)
+				return
+			}
+
+			user, err := userService.AuthenticateToken(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidCredentials) {
+					respondUnauthorized(w, r, "Invalid or expired token", err)
+				} else {
+					respondWithError(w, r, http.StatusInternalServerError, "Failed to authenticate request", err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// RequireUser rejects requests that Authenticate didn't attach a user to.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.UserFromContext(r.Context()); !ok {
+			respondUnauthorized(w, r, "Authentication required", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSelfOrAdmin guards routes below /users/{userID}: the caller must be signed in as
+// that user, or hold an admin API key. Other users are rejected with 403.
+func RequireSelfOrAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+			return
+		}
+		if !allowUser(w, r, userID) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowUser is RequireSelfOrAdmin for user IDs taken from elsewhere in the request, such as
+// a query parameter. If the caller may not act for the user, it has already responded
+// with 401 or 403.
+func allowUser(w http.ResponseWriter, r *http.Request, userID uint64) bool {
+	if role, ok := auth.RoleFromContext(r.Context()); ok && role.AtLeast(auth.RoleAdmin) {
+		return true
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorized(w, r, "Authentication required", nil)
+		return false
+	}
+	if userID != user.ID {
+		respondWithError(w, r, http.StatusForbidden, "Not allowed to access another user's data", nil)
+		return false
+	}
+	return true
+}
+
+// respondUnauthorized responds with 401 and the challenge clients need to retry with a token.
+func respondUnauthorized(w http.ResponseWriter, r *http.Request, message string, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	respondWithError(w, r, http.StatusUnauthorized, message, err)
+}
+
+// APIKeyAuth resolves the X-API-Key header to a role and stores it in the request context.
+// Requests without the header pass through; an unknown key is rejected with 401.
+func APIKeyAuth(keys auth.APIKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-API-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			role, ok := keys.Lookup(key)
+			if !ok {
+				respondWithError(w, r, http.StatusUnauthorized, "Invalid API key", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithRole(r.Context(), role)))
+		})
+	}
+}
+
+// RequireRoleForWrites lets safe methods (GET, HEAD, OPTIONS) through and requires
+// every other request to carry an API key granting at least min.
+func RequireRoleForWrites(min auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			role, ok := auth.RoleFromContext(r.Context())
+			if !ok {
+				respondWithError(w, r, http.StatusUnauthorized, "API key required", nil)
+				return
+			}
+			if !role.AtLeast(min) {
+				respondWithError(w, r, http.StatusForbidden, fmt.Sprintf("Role %s is not allowed to modify this resource, %s or higher required", role, min), nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole requires every request to carry an API key granting at least min.
+func RequireRole(min auth.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := auth.RoleFromContext(r.Context())
+			if !ok {
+				respondWithError(w, r, http.StatusUnauthorized, "API key required", nil)
+				return
+			}
+			if !role.AtLeast(min) {
+				respondWithError(w, r, http.StatusForbidden, fmt.Sprintf("Role %s is not allowed to access this resource, %s or higher required", role, min), nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+func TestAllowUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(context.Context) context.Context
+		wantOK   bool
+		wantCode int
+	}{
+		{"self", func(ctx context.Context) context.Context { return auth.WithUser(ctx, &domain.User{ID: 7}) }, true, http.StatusOK},
+		{"admin key", func(ctx context.Context) context.Context { return auth.WithRole(ctx, auth.RoleAdmin) }, true, http.StatusOK},
+		{"other user", func(ctx context.Context) context.Context { return auth.WithUser(ctx, &domain.User{ID: 8}) }, false, http.StatusForbidden},
+		{"editor key", func(ctx context.Context) context.Context { return auth.WithRole(ctx, auth.RoleEditor) }, false, http.StatusUnauthorized},
+		{"anonymous", func(ctx context.Context) context.Context { return ctx }, false, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(tt.ctx(r.Context()))
+			rec := httptest.NewRecorder()
+			if ok := allowUser(rec, r, 7); ok != tt.wantOK || rec.Code != tt.wantCode {
+				t.Errorf("allowUser() = %v with status %d, want %v with %d", ok, rec.Code, tt.wantOK, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type CalculatorHandler struct {
+	calculatorService service.CalculatorService
+}
+
+// NewCalculatorHandler creates a handler for crafting calculation requests.
+func NewCalculatorHandler(calculatorService service.CalculatorService) *CalculatorHandler {
+	return &CalculatorHandler{
+		calculatorService: calculatorService,
+	}
+}
+
+// RegisterCalculatorRoutes sets up the routes for the calculator on the provided router.
+func (h *CalculatorHandler) RegisterCalculatorRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodPost, "/", h.Calculate)
+	r.MethodFunc(http.MethodPost, "/shopping-list", h.ShoppingList)
+	r.MethodFunc(http.MethodPost, "/throughput", h.PlanThroughput)
+	r.MethodFunc(http.MethodPost, "/craftable", h.Craftable)
+}
+
+// --- Calculate ---
+func (h *CalculatorHandler) Calculate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.CalculateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	// Preferences are private, so only their owner or an admin may plan with them
+	if req.UserID != nil && !allowUser(w, r, *req.UserID) {
+		return
+	}
+
+	result, err := h.calculatorService.Calculate(ctx, req)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipes for this item form a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to calculate crafting requirements", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- ShoppingList ---
+func (h *CalculatorHandler) ShoppingList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.ShoppingListRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	if req.UserID != nil && !allowUser(w, r, *req.UserID) {
+		return
+	}
+
+	list, err := h.calculatorService.ShoppingList(ctx, req)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipes for this item form a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to calculate shopping list", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- PlanThroughput ---
+func (h *CalculatorHandler) PlanThroughput(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.ThroughputRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	if req.UserID != nil && !allowUser(w, r, *req.UserID) {
+		return
+	}
+
+	plan, err := h.calculatorService.PlanThroughput(ctx, req)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipes for this item form a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to plan throughput", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(plan); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- Craftable ---
+func (h *CalculatorHandler) Craftable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.CraftableRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	craftable, err := h.calculatorService.Craftable(ctx, req)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to find craftable recipes", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(craftable); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
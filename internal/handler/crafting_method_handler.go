@@ -27,8 +27,11 @@ func NewCraftingMethodHandler(craftingMethodService service.CraftingMethodServic
 func (h *CraftingMethodHandler) RegisterCraftingMethodRoutes(r chi.Router, listHandler http.HandlerFunc) {
 	r.MethodFunc(http.MethodGet, "/", listHandler)
 	r.MethodFunc(http.MethodPost, "/", h.CreateCraftingMethod)
+	r.MethodFunc(http.MethodGet, "/by-slug/{slug}", h.GetCraftingMethodBySlug)
+	r.MethodFunc(http.MethodPut, "/by-slug/{slug}", h.PutCraftingMethodBySlug)
 	r.MethodFunc(http.MethodGet, "/{methodID}", h.GetCraftingMethodByID)
 	r.MethodFunc(http.MethodPut, "/{methodID}", h.UpdateCraftingMethod)
+	r.MethodFunc(http.MethodPatch, "/{methodID}", h.PatchCraftingMethod)
 	r.MethodFunc(http.MethodDelete, "/{methodID}", h.DeleteCraftingMethod)
 }
 
@@ -73,6 +76,7 @@ func (h *CraftingMethodHandler) CreateCraftingMethod(w http.ResponseWriter, r *h
 	}
 
 	// Send successful response
+	setETag(w, newMethod.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(newMethod); err != nil {
@@ -100,6 +104,10 @@ func (h *CraftingMethodHandler) GetCraftingMethodByID(w http.ResponseWriter, r *
 		return
 	}
 
+	if notModified(w, r, versionETag(item.Version), item.UpdatedAt) {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(item); err != nil {
@@ -107,6 +115,90 @@ func (h *CraftingMethodHandler) GetCraftingMethodByID(w http.ResponseWriter, r *
 	}
 }
 
+// --- GetCraftingMethodBySlug ---
+func (h *CraftingMethodHandler) GetCraftingMethodBySlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	method, err := h.craftingMethodService.GetCraftingMethodBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Crafting method not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve crafting method", err)
+		}
+		return
+	}
+
+	if notModified(w, r, versionETag(method.Version), method.UpdatedAt) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(method); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- PutCraftingMethodBySlug ---
+func (h *CraftingMethodHandler) PutCraftingMethodBySlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.PutCraftingMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	method, created, err := h.craftingMethodService.PutCraftingMethodBySlug(ctx, slug, req, ifMatch)
+	if err != nil {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Crafting method was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method was modified concurrently, retry the request", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method name conflicts with an existing crafting method", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to store crafting method", err)
+		}
+		return
+	}
+
+	// 201 only for the request that created the crafting method; repeats of it are plain updates
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	setETag(w, method.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(method); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
 // --- UpdateCraftingMethod ---
 func (h *CraftingMethodHandler) UpdateCraftingMethod(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -117,6 +209,11 @@ func (h *CraftingMethodHandler) UpdateCraftingMethod(w http.ResponseWriter, r *h
 		return
 	}
 
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
 	var req service.UpdateCraftingMethodRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
@@ -137,9 +234,13 @@ func (h *CraftingMethodHandler) UpdateCraftingMethod(w http.ResponseWriter, r *h
 	}
 
 	// Call the service
-	updatedMethod, err := h.craftingMethodService.UpdateCraftingMethod(ctx, methodID, req)
+	updatedMethod, err := h.craftingMethodService.UpdateCraftingMethod(ctx, methodID, req, ifMatch)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Crafting method was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method was modified concurrently, retry the request", err)
+		} else if errors.Is(err, storage.ErrNotFound) {
 			respondWithError(w, r, http.StatusNotFound, "Crafting method not found", err)
 		} else if errors.Is(err, storage.ErrDuplicateEntry) {
 			respondWithError(w, r, http.StatusConflict, "Crafting method name or slug conflicts with an existing crafting method", err)
@@ -150,6 +251,71 @@ func (h *CraftingMethodHandler) UpdateCraftingMethod(w http.ResponseWriter, r *h
 	}
 
 	// Send successful response
+	setETag(w, updatedMethod.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(updatedMethod); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- PatchCraftingMethod ---
+// PatchCraftingMethod applies a JSON Merge Patch: absent fields stay unchanged and null clears a field.
+func (h *CraftingMethodHandler) PatchCraftingMethod(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	methodIDStr := chi.URLParam(r, "methodID")
+	methodID, err := strconv.ParseUint(methodIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid method ID format", err)
+		return
+	}
+
+	if !acceptMergePatch(w, r) {
+		return
+	}
+
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.PatchCraftingMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON merge patch", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	updatedMethod, err := h.craftingMethodService.PatchCraftingMethod(ctx, methodID, req, ifMatch)
+	if err != nil {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Crafting method was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method was modified concurrently, retry the request", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Crafting method not found", err)
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method name or slug conflicts with an existing crafting method", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to update crafting method", err)
+		}
+		return
+	}
+
+	setETag(w, updatedMethod.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(updatedMethod); err != nil {
@@ -167,9 +333,18 @@ func (h *CraftingMethodHandler) DeleteCraftingMethod(w http.ResponseWriter, r *h
 		return
 	}
 
-	err = h.craftingMethodService.DeleteCraftingMethod(ctx, methodID)
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.craftingMethodService.DeleteCraftingMethod(ctx, methodID, ifMatch)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Crafting method was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Crafting method was modified concurrently, retry the request", err)
+		} else if errors.Is(err, storage.ErrNotFound) {
 			respondWithError(w, r, http.StatusNotFound, "Crafting method not found", err)
 		} else {
 			// Handle other potential errors (e.g., FK constraints if not CASCADE)
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type EnergyHandler struct {
+	energyService service.EnergyService
+}
+
+// NewEnergyHandler creates a handler for energy reports of crafting plans.
+func NewEnergyHandler(energyService service.EnergyService) *EnergyHandler {
+	return &EnergyHandler{
+		energyService: energyService,
+	}
+}
+
+// RegisterEnergyRoutes sets up the routes for energy reports on the provided router.
+func (h *EnergyHandler) RegisterEnergyRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodPost, "/", h.Report)
+}
+
+// --- Report ---
+func (h *EnergyHandler) Report(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.EnergyReportRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	report, err := h.energyService.Report(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidReference) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Plan references an unknown recipe", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to build energy report", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
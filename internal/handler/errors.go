@@ -2,41 +2,49 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"log/slog"
 	"net/http"
-	"reflect"
-	"strings"
 
+	"github.com/dubbie/calculator-api/internal/logging"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
+	"github.com/dubbie/calculator-api/internal/service"
 	"github.com/go-playground/validator/v10"
 )
 
-var validate = validator.New()
-
-func init() {
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-
-		if name == "-" {
-			return ""
-		}
-
-		return name
-	})
-}
+// validate checks request payloads, reporting fields by their JSON names.
+var validate = service.NewValidator()
 
 type APIError struct {
-	Status  int    `json:"status"`
-	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"` // Quote it when reporting the error
 }
 
 func respondWithError(w http.ResponseWriter, r *http.Request, code int, message string, originalError error, details ...any) {
-	// Log the original error with request context for debugging
-	fmt.Printf("Api Error: Status=%d, Message=%s, Request=%s, Error=%v, Details=%v\n", code, message, r.URL.String(), originalError, details)
+	ctx := r.Context()
+	logger := loggerFromContext(ctx)
+
+	// Log the original error with request context for debugging. Client errors are routine,
+	// server errors need attention.
+	level := slog.LevelInfo
+	if code >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logger.Log(ctx, level, "API error",
+		"status", code,
+		"message", message,
+		"method", r.Method,
+		"url", r.URL.String(),
+		"error", originalError,
+		"details", details,
+	)
 
 	responseBody := APIError{
-		Status:  code,
-		Message: message,
+		Status:    code,
+		Message:   message,
+		RequestID: logging.RequestID(ctx),
 	}
 
 	if len(details) > 0 {
@@ -54,11 +62,24 @@ func respondWithError(w http.ResponseWriter, r *http.Request, code int, message
 	w.WriteHeader(code)
 
 	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
-		fmt.Printf("Error encoding error response: %v\n", err)
+		logger.ErrorContext(ctx, "Failed to encode error response", "error", err)
 		http.Error(w, `{"status":500,"message":"Internal Server Error encoding error response"}`, http.StatusInternalServerError)
 	}
 }
 
+// respondWithCycleError responds with 422 if err reports a recipe dependency cycle.
+// It returns false, without writing anything, for any other error.
+func respondWithCycleError(w http.ResponseWriter, r *http.Request, message string, err error) bool {
+	var cycleErr *recipegraph.CycleError
+	if !errors.As(err, &cycleErr) {
+		return false
+	}
+	respondWithError(w, r, http.StatusUnprocessableEntity, message, err, map[string]any{
+		"cycle_item_ids": cycleErr.ItemIDs,
+	})
+	return true
+}
+
 // validationErrorResponse structures the validation error details.
 type validationErrorResponse struct {
 	Field   string `json:"field"`
@@ -74,18 +95,7 @@ func formatValidationErrors(err error) []validationErrorResponse {
 		for _, err := range errs {
 			// Use JSON field name from the tag name func we registered
 			field := err.Field()
-			message := fmt.Sprintf("failed on '%s' validation", err.Tag())
-
-			switch err.Tag() {
-			case "required":
-				message = "is required"
-			case "min":
-				message = fmt.Sprintf("must be at least %s characters long", err.Param())
-			case "max":
-				message = fmt.Sprintf("must be at most %s characters long", err.Param())
-			case "url":
-				message = "must be a valid URL"
-			}
+			message := service.FieldErrorMessage(err)
 			validationErrors = append(validationErrors, validationErrorResponse{
 				Field:   field,
 				Message: message,
@@ -94,7 +104,7 @@ func formatValidationErrors(err error) []validationErrorResponse {
 	} else {
 		// Handle non-validation errors if they somehow reach here
 		// Or just return a generic error detail
-		fmt.Printf("Warning: formatValidationErrors received non-validation error: %v\n", err)
+		slog.Warn("formatValidationErrors received a non-validation error", "error", err)
 	}
 
 	return validationErrors
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+)
+
+// cacheControl lets clients keep responses but makes them revalidate before each reuse,
+// which the validators below turn into a cheap 304.
+const cacheControl = "private, no-cache"
+
+// versionETag formats a row version as a strong entity tag.
+func versionETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// setETag sets the ETag header to the given row version.
+func setETag(w http.ResponseWriter, version uint64) {
+	w.Header().Set("ETag", versionETag(version))
+}
+
+// listETag is a weak entity tag for a list page, built from the state of the whole filtered
+// list. Weak because it says the page is current, not that it is byte-for-byte the same.
+func listETag(state pagination.ListState) string {
+	return `W/"` + strconv.FormatInt(state.Count, 10) + "-" + strconv.FormatInt(state.LastModified.UnixNano(), 10) +
+		"-" + strconv.FormatUint(state.Revision, 10) + `"`
+}
+
+// bodyETag is a weak entity tag for a response body, for responses without a cheaper
+// validator. It still spares the client the download of an unchanged body.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// entityTags splits the comma-separated entity tags of a request header.
+func entityTags(r *http.Request, header string) []string {
+	var tags []string
+	for _, value := range r.Header.Values(header) {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// notModified sets the caching headers of a GET response and reports whether the client's
+// copy is still current, in which case it has already responded with 304 Not Modified.
+// If-None-Match takes precedence over If-Modified-Since. A zero lastModified is left out.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	header := w.Header()
+	header.Set("Cache-Control", cacheControl)
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	fresh := false
+	if tags := entityTags(r, "If-None-Match"); len(tags) > 0 {
+		// If-None-Match uses weak comparison, which ignores the W/ prefix
+		for _, tag := range tags {
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				fresh = true
+				break
+			}
+		}
+	} else if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		// HTTP dates have whole seconds
+		fresh = !lastModified.Truncate(time.Second).After(since)
+	}
+
+	if fresh {
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return fresh
+}
+
+// parseIfMatch reads the versions listed in the If-Match headers. A missing header or "*"
+// yields no versions, which means the write is unconditional. Weak tags never match, as
+// If-Match uses strong comparison; ok is false when tags were sent but none can match.
+func parseIfMatch(r *http.Request) (versions []uint64, ok bool) {
+	tags := entityTags(r, "If-Match")
+	if len(tags) == 0 {
+		return nil, true
+	}
+
+	for _, tag := range tags {
+		if tag == "*" {
+			return nil, true
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, len(versions) > 0
+}
+
+// ifMatchVersions is parseIfMatch for handlers, responding with 412 when no tag can match.
+func ifMatchVersions(w http.ResponseWriter, r *http.Request) ([]uint64, bool) {
+	versions, ok := parseIfMatch(r)
+	if !ok {
+		respondWithError(w, r, http.StatusPreconditionFailed, "If-Match does not match the current version", nil)
+	}
+	return versions, ok
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dubbie/calculator-api/internal/app/dataset"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler creates a handler for exports of the game dataset.
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RegisterExportRoutes sets up the routes for dataset exports on the provided router.
+func (h *ExportHandler) RegisterExportRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodGet, "/", h.Export)
+}
+
+// --- Export ---
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ds, err := h.exportService.Export(ctx)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to export dataset", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := dataset.EncodeJSON(w, ds); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
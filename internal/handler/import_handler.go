@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/dubbie/calculator-api/internal/app/dataset"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImportBodyBytes caps the size of an uploaded dataset.
+const maxImportBodyBytes = 32 << 20
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// NewImportHandler creates a handler for bulk imports of game data.
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// RegisterImportRoutes sets up the routes for bulk imports on the provided router.
+func (h *ImportHandler) RegisterImportRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodPost, "/", h.Import)
+}
+
+// --- Import ---
+// Accepts a JSON dataset, or with Content-Type text/csv one kind of rows selected by
+// the "type" query parameter. The "mode" query parameter picks atomic or skip_invalid.
+// The report is returned with 200 if the import was committed and 422 if it was rolled back.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body := http.MaxBytesReader(w, r.Body, maxImportBodyBytes)
+	defer body.Close()
+
+	mediaType := "application/json"
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, "Invalid Content-Type header", err)
+			return
+		}
+		mediaType = parsed
+	}
+
+	var (
+		ds  domain.Dataset
+		err error
+	)
+	switch mediaType {
+	case "application/json":
+		ds, err = dataset.DecodeJSON(body)
+	case "text/csv":
+		ds, err = dataset.DecodeCSV(body, r.URL.Query().Get("type"))
+	default:
+		respondWithError(w, r, http.StatusUnsupportedMediaType, "Datasets must be sent as application/json or text/csv", nil)
+		return
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, r, http.StatusRequestEntityTooLarge, "Dataset is too large", err)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Invalid dataset", err, err.Error())
+		}
+		return
+	}
+
+	req := service.ImportRequest{
+		Dataset: ds,
+		Mode:    domain.ImportMode(r.URL.Query().Get("mode")),
+	}
+	report, err := h.importService.Import(ctx, req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to import dataset", err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+}
+
+// NewInventoryHandler creates a handler for users' inventories.
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// RegisterInventoryRoutes sets up the routes for a user's inventory on the provided router.
+// The router is expected to be mounted below a path containing {userID}, and listHandler
+// to be scoped to that user with ScopeInventoryToUser. Guard the router with
+// RequireSelfOrAdmin so only that user, or an admin, can read or change the inventory.
+func (h *InventoryHandler) RegisterInventoryRoutes(r chi.Router, listHandler http.HandlerFunc) {
+	r.MethodFunc(http.MethodGet, "/", listHandler)
+	r.MethodFunc(http.MethodPost, "/adjust", h.AdjustInventory)
+	r.MethodFunc(http.MethodGet, "/{itemID}", h.GetInventoryItem)
+	r.MethodFunc(http.MethodPut, "/{itemID}", h.SetQuantity)
+	r.MethodFunc(http.MethodPost, "/{itemID}/add", h.AddQuantity)
+	r.MethodFunc(http.MethodPost, "/{itemID}/remove", h.RemoveQuantity)
+	r.MethodFunc(http.MethodDelete, "/{itemID}", h.DeleteInventoryItem)
+}
+
+// ScopeInventoryToUser restricts an inventory list to the user in the route.
+func ScopeInventoryToUser(r *http.Request, params *pagination.ListParams[domain.InventoryFilters]) error {
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+	params.Filters.UserID = userID
+	return nil
+}
+
+// --- GetInventoryItem ---
+func (h *InventoryHandler) GetInventoryItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, itemID, ok := parseInventoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.inventoryService.GetInventoryItem(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not in inventory", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve inventory item", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- SetQuantity ---
+func (h *InventoryHandler) SetQuantity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, itemID, ok := parseInventoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.SetInventoryQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	entry, err := h.inventoryService.SetQuantity(ctx, userID, itemID, req)
+	if err != nil {
+		respondWithInventoryError(w, r, "Failed to set inventory quantity", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- AddQuantity ---
+func (h *InventoryHandler) AddQuantity(w http.ResponseWriter, r *http.Request) {
+	h.changeQuantity(w, r, h.inventoryService.AddQuantity, "Failed to add to inventory")
+}
+
+// --- RemoveQuantity ---
+func (h *InventoryHandler) RemoveQuantity(w http.ResponseWriter, r *http.Request) {
+	h.changeQuantity(w, r, h.inventoryService.RemoveQuantity, "Failed to remove from inventory")
+}
+
+// --- AdjustInventory ---
+func (h *InventoryHandler) AdjustInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	var req service.AdjustInventoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	entries, err := h.inventoryService.AdjustInventory(ctx, userID, req)
+	if err != nil {
+		respondWithInventoryError(w, r, "Failed to adjust inventory", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- DeleteInventoryItem ---
+func (h *InventoryHandler) DeleteInventoryItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, itemID, ok := parseInventoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.inventoryService.DeleteInventoryItem(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not in inventory", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete inventory item", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type changeQuantityFunc func(ctx context.Context, userID, itemID uint64, req service.ChangeInventoryQuantityRequest) (*domain.InventoryItem, error)
+
+// changeQuantity handles the add and remove routes, which only differ in the service call.
+func (h *InventoryHandler) changeQuantity(w http.ResponseWriter, r *http.Request, change changeQuantityFunc, failureMessage string) {
+	ctx := r.Context()
+	userID, itemID, ok := parseInventoryPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.ChangeInventoryQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	entry, err := change(ctx, userID, itemID, req)
+	if err != nil {
+		respondWithInventoryError(w, r, failureMessage, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// parseInventoryPath reads {userID} and {itemID}, responding with 400 if either is malformed.
+func parseInventoryPath(w http.ResponseWriter, r *http.Request) (userID, itemID uint64, ok bool) {
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+		return 0, 0, false
+	}
+	itemID, err = strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return 0, 0, false
+	}
+	return userID, itemID, true
+}
+
+// respondWithInventoryError maps the errors of inventory writes to responses.
+func respondWithInventoryError(w http.ResponseWriter, r *http.Request, failureMessage string, err error) {
+	if errors.Is(err, storage.ErrInsufficientQuantity) {
+		respondWithError(w, r, http.StatusConflict, "Inventory quantity cannot go below zero", err)
+	} else if errors.Is(err, storage.ErrInvalidReference) {
+		respondWithError(w, r, http.StatusUnprocessableEntity, "User or item does not exist", err)
+	} else {
+		respondWithError(w, r, http.StatusInternalServerError, failureMessage, err)
+	}
+}
@@ -27,11 +27,36 @@ func NewItemHandler(itemService service.ItemService) *ItemHandler {
 func (h *ItemHandler) RegisterItemRoutes(r chi.Router, listHandler http.HandlerFunc) {
 	r.MethodFunc(http.MethodGet, "/", listHandler)
 	r.MethodFunc(http.MethodPost, "/", h.CreateItem)
+	r.MethodFunc(http.MethodGet, "/by-slug/{slug}", h.GetItemBySlug)
+	r.MethodFunc(http.MethodPut, "/by-slug/{slug}", h.PutItemBySlug)
 	r.MethodFunc(http.MethodGet, "/{itemID}", h.GetItemByID)
 	r.MethodFunc(http.MethodPut, "/{itemID}", h.UpdateItem)
+	r.MethodFunc(http.MethodPatch, "/{itemID}", h.PatchItem)
 	r.MethodFunc(http.MethodDelete, "/{itemID}", h.DeleteItem)
 }
 
+// RequireItem guards routes below /items/{itemID} that list related resources, so a missing
+// item is reported with 404 like /items/{itemID} itself rather than as an empty list.
+func (h *ItemHandler) RequireItem(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+			return
+		}
+
+		if _, err := h.itemService.GetItemByID(r.Context(), itemID); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+			} else {
+				respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve item", err)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // --- CreateItem ---
 func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context() // Get context early
@@ -73,6 +98,7 @@ func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send successful response
+	setETag(w, newItem.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(newItem); err != nil {
@@ -100,6 +126,10 @@ func (h *ItemHandler) GetItemByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, versionETag(item.Version), item.UpdatedAt) {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(item); err != nil {
@@ -107,6 +137,90 @@ func (h *ItemHandler) GetItemByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// --- GetItemBySlug ---
+func (h *ItemHandler) GetItemBySlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	item, err := h.itemService.GetItemBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve item", err)
+		}
+		return
+	}
+
+	if notModified(w, r, versionETag(item.Version), item.UpdatedAt) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- PutItemBySlug ---
+func (h *ItemHandler) PutItemBySlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.PutItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	item, created, err := h.itemService.PutItemBySlug(ctx, slug, req, ifMatch)
+	if err != nil {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Item was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Item was modified concurrently, retry the request", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Item name conflicts with an existing item", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to store item", err)
+		}
+		return
+	}
+
+	// 201 only for the request that created the item; repeats of it are plain updates
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	setETag(w, item.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
 // --- UpdateItem ---
 func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -117,6 +231,11 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
 	var req service.UpdateItemRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
@@ -137,9 +256,13 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Call the service
-	updatedItem, err := h.itemService.UpdateItem(ctx, itemID, req)
+	updatedItem, err := h.itemService.UpdateItem(ctx, itemID, req, ifMatch)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Item was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Item was modified concurrently, retry the request", err)
+		} else if errors.Is(err, storage.ErrNotFound) {
 			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
 		} else if errors.Is(err, storage.ErrDuplicateEntry) {
 			respondWithError(w, r, http.StatusConflict, "Item name or slug conflicts with an existing item", err)
@@ -150,6 +273,71 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send successful response
+	setETag(w, updatedItem.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(updatedItem); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- PatchItem ---
+// PatchItem applies a JSON Merge Patch: absent fields stay unchanged and null clears a field.
+func (h *ItemHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	itemIDStr := chi.URLParam(r, "itemID")
+	itemID, err := strconv.ParseUint(itemIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return
+	}
+
+	if !acceptMergePatch(w, r) {
+		return
+	}
+
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.PatchItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON merge patch", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	updatedItem, err := h.itemService.PatchItem(ctx, itemID, req, ifMatch)
+	if err != nil {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Item was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Item was modified concurrently, retry the request", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Item name or slug conflicts with an existing item", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to update item", err)
+		}
+		return
+	}
+
+	setETag(w, updatedItem.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(updatedItem); err != nil {
@@ -167,9 +355,18 @@ func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.itemService.DeleteItem(ctx, itemID)
+	ifMatch, ok := ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.itemService.DeleteItem(ctx, itemID, ifMatch)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
+		if errors.Is(err, service.ErrPreconditionFailed) {
+			respondWithError(w, r, http.StatusPreconditionFailed, "Item was modified since it was read", err)
+		} else if errors.Is(err, storage.ErrConflict) {
+			respondWithError(w, r, http.StatusConflict, "Item was modified concurrently, retry the request", err)
+		} else if errors.Is(err, storage.ErrNotFound) {
 			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
 		} else {
 			// Handle other potential errors (e.g., FK constraints if not CASCADE)
@@ -1,17 +1,27 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/dubbie/calculator-api/internal/app/pagination"
 	"github.com/dubbie/calculator-api/internal/service"
 	"github.com/dubbie/calculator-api/internal/storage"
 )
 
+// ListScope adjusts parsed list parameters from the request, e.g. to restrict a list to
+// the parent resource named in the route. Returning an error rejects the request with 400.
+type ListScope[F any] func(r *http.Request, params *pagination.ListParams[F]) error
+
 // MakeListHandler creates a generic http.HandlerFunc for listing resources.
-func MakeListHandler[T any, F any](lister service.ListService[T, F]) http.HandlerFunc {
+// Scopes are applied in order after the query parameters are parsed. Lists are paginated
+// by page number unless the request carries a cursor parameter. Responses carry an ETag,
+// for the filtered list by page number or for the page itself in cursor mode, and
+// requests with a matching If-None-Match are answered with 304.
+func MakeListHandler[T any, F any](lister service.ListService[T, F], scopes ...ListScope[F]) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		queryParams := r.URL.Query()
@@ -23,7 +33,42 @@ func MakeListHandler[T any, F any](lister service.ListService[T, F]) http.Handle
 			return
 		}
 
-		response, err := lister.List(ctx, params)
+		for _, scope := range scopes {
+			if err := scope(r, &params); err != nil {
+				respondWithError(w, r, http.StatusBadRequest, "Invalid path parameters", err)
+				return
+			}
+		}
+
+		// A cursor parameter switches to keyset pagination, which has a different response shape
+		var (
+			response any
+			link     string
+		)
+		if params.CursorMode {
+			var page pagination.CursorResponse[T]
+			page, err = lister.ListByCursor(ctx, params)
+			page = page.WithURLs(r.URL)
+			response, link = page, page.Link()
+		} else {
+			// Any change to the filtered rows changes their state, so an unchanged state means the
+			// client's copy of this page is still current. The state's count is the page's total.
+			// Lists go without Last-Modified: deleting any but the newest row leaves it unchanged.
+			state, stateErr := lister.ListState(ctx, params)
+			if stateErr != nil {
+				respondWithError(w, r, http.StatusInternalServerError, "Failed to list resources", stateErr)
+				return
+			}
+			if notModified(w, r, listETag(state), time.Time{}) {
+				return
+			}
+			params.Total = &state.Count
+
+			var page pagination.PaginatedResponse[T]
+			page, err = lister.List(ctx, params)
+			page = page.WithURLs(r.URL)
+			response, link = page, page.Link()
+		}
 		if err != nil {
 			// Map errors and respond using the helper
 			statusCode := http.StatusInternalServerError
@@ -31,18 +76,35 @@ func MakeListHandler[T any, F any](lister service.ListService[T, F]) http.Handle
 			if errors.Is(err, storage.ErrNotFound) {
 				statusCode = http.StatusNotFound
 				message = "Resource not found"
+			} else if errors.Is(err, pagination.ErrInvalidCursor) {
+				statusCode = http.StatusBadRequest
+				message = "Invalid cursor"
 			}
 
+			// The validators describe the list, not this error
+			for _, header := range []string{"Cache-Control", "ETag", "Last-Modified"} {
+				w.Header().Del(header)
+			}
 			respondWithError(w, r, statusCode, message, err)
 			return
 		}
 
+		var body bytes.Buffer
+		if err := json.NewEncoder(&body).Encode(response); err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+			return
+		}
+		// Cursor mode counts nothing, so its pages are validated by their content instead
+		if params.CursorMode && notModified(w, r, bodyETag(body.Bytes()), time.Time{}) {
+			return
+		}
+
 		// Send success response
+		if link != "" {
+			w.Header().Set("Link", link)
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			// Log the encoding error using respondWithError (status already sent)
-			respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
-		}
+		_, _ = w.Write(body.Bytes()) // The status is sent, nothing left to report to
 	}
 }
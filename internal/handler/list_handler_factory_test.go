@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+)
+
+type testRow struct {
+	ID        uint64    `json:"id"`
+	Version   uint64    `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+type testFilters struct{}
+
+// rowLister lists its rows in memory, computing their state like the MySQL stores do.
+type rowLister struct {
+	rows []testRow
+}
+
+func (l *rowLister) List(ctx context.Context, params pagination.ListParams[testFilters]) (pagination.PaginatedResponse[testRow], error) {
+	return pagination.NewPaginatedResponse(l.rows, int64(len(l.rows)), params.Page, params.PerPage), nil
+}
+
+func (l *rowLister) ListByCursor(ctx context.Context, params pagination.ListParams[testFilters]) (pagination.CursorResponse[testRow], error) {
+	return pagination.NewCursorResponse(l.rows, pagination.CursorPage{}, params.PerPage), nil
+}
+
+func (l *rowLister) ListState(ctx context.Context, params pagination.ListParams[testFilters]) (pagination.ListState, error) {
+	state := pagination.ListState{Count: int64(len(l.rows))}
+	for _, row := range l.rows {
+		if row.UpdatedAt.After(state.LastModified) {
+			state.LastModified = row.UpdatedAt
+		}
+		state.Revision += row.Version
+	}
+	return state, nil
+}
+
+func TestMakeListHandlerAfterDelete(t *testing.T) {
+	older := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newest := older.Add(time.Hour)
+	lister := &rowLister{rows: []testRow{{ID: 1, Version: 1, UpdatedAt: older}, {ID: 2, Version: 1, UpdatedAt: newest}}}
+	h := MakeListHandler[testRow, testFilters](lister)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GET = %d with ETag %q, want 200 with an ETag", first.Code, etag)
+	}
+	if lastModified := first.Header().Get("Last-Modified"); lastModified != "" {
+		t.Errorf("Last-Modified = %q, want none on lists", lastModified)
+	}
+	if rec := get("If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("GET with the current ETag = %d, want 304", rec.Code)
+	}
+
+	// Deleting the older row leaves the newest updated_at as it was
+	lister.rows = lister.rows[1:]
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"If-None-Match with the old ETag", "If-None-Match", etag},
+		{"If-Modified-Since the newest row", "If-Modified-Since", newest.Format(http.TimeFormat)},
+		{"If-Modified-Since now", "If-Modified-Since", time.Now().UTC().Format(http.TimeFormat)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.header, tt.value)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET = %d, want 200 after a delete", rec.Code)
+			}
+			var page pagination.PaginatedResponse[testRow]
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("decoding the page: %v", err)
+			}
+			if page.Total != 1 || len(page.Data) != 1 {
+				t.Errorf("page = total %d with %d rows, want 1 row", page.Total, len(page.Data))
+			}
+			if rec.Header().Get("ETag") == etag {
+				t.Error("ETag unchanged after a delete")
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+)
+
+// mergePatchMediaType is the media type of JSON Merge Patch (RFC 7396) documents.
+const mergePatchMediaType = "application/merge-patch+json"
+
+// acceptMergePatch checks that a PATCH request carries a merge patch. Plain JSON and a
+// missing Content-Type are accepted too; anything else is rejected with 415 and false.
+func acceptMergePatch(w http.ResponseWriter, r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid Content-Type header", err)
+		return false
+	}
+	if mediaType != mergePatchMediaType && mediaType != "application/json" {
+		w.Header().Set("Accept-Patch", mergePatchMediaType)
+		respondWithError(w, r, http.StatusUnsupportedMediaType, "Patches must be sent as "+mergePatchMediaType, nil)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels requests no route matched, keeping arbitrary paths out of the labels.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method no route serves. Clients choose the method, so
+// passing it through would let them add series without bound.
+const otherMethod = "other"
+
+// knownMethods are the methods labeled as themselves.
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// RouteMetrics counts requests and times them, labeled by method, chi route pattern
+// (e.g. "/api/v1/items/{itemID}") and response status. Every label has a bounded set of
+// values, whatever the client sends.
+func RouteMetrics(registry *metrics.Registry) func(http.Handler) http.Handler {
+	requests := registry.NewCounterVec(
+		"http_requests_total",
+		"Number of HTTP requests served.",
+		"method", "route", "status",
+	)
+	durations := registry.NewHistogramVec(
+		"http_request_duration_seconds",
+		"Time taken to serve HTTP requests.",
+		metrics.DefaultBuckets,
+		"method", "route", "status",
+	)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			// The pattern is only complete once routing has finished
+			route := routeLabel(r)
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			method := r.Method
+			if !knownMethods[method] {
+				method = otherMethod
+			}
+
+			labels := []string{method, route, strconv.Itoa(status)}
+			requests.Inc(labels...)
+			durations.Observe(time.Since(start).Seconds(), labels...)
+		})
+	}
+}
+
+// MetricsHandler serves the registry to admin API keys only, as metrics reveal traffic and
+// database details. It guards /metrics on the API port and on the admin server alike.
+func MetricsHandler(keys auth.APIKeys, registry *metrics.Registry) http.Handler {
+	return APIKeyAuth(keys)(RequireRole(auth.RoleAdmin)(registry))
+}
+
+// routeLabel returns the pattern of the route that served r. Requests that never reached
+// a route, which chi answers with 404 or 405, get unmatchedRoute; their pattern is empty or
+// only names the subrouter they stopped in, e.g. "/api/v1/*".
+func routeLabel(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return unmatchedRoute
+	}
+	pattern := rctx.RoutePattern()
+	if pattern == "" || strings.HasSuffix(pattern, "/*") {
+		return unmatchedRoute
+	}
+	return pattern
+}
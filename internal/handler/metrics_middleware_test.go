@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRouteMetricsLabels(t *testing.T) {
+	registry := metrics.NewRegistry()
+	r := chi.NewRouter()
+	r.Use(RouteMetrics(registry))
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Route("/items", func(r chi.Router) {
+			r.Get("/{itemID}", func(w http.ResponseWriter, r *http.Request) {})
+		})
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"matched route", http.MethodGet, "/api/v1/items/7", `method="GET",route="/api/v1/items/{itemID}",status="200"`},
+		{"unknown path", http.MethodGet, "/api/v1/whatever/123", `method="GET",route="unmatched",status="404"`},
+		{"unknown path in subrouter", http.MethodGet, "/api/v1/items/7/extra", `method="GET",route="unmatched",status="404"`},
+		{"path outside the API", http.MethodGet, "/random-" + strings.Repeat("x", 40), `method="GET",route="unmatched",status="404"`},
+		{"method not allowed", http.MethodDelete, "/api/v1/items/7", `method="DELETE",route="unmatched",status="405"`},
+		{"unknown method", "PURGE", "/api/v1/items/7", `method="other",route="unmatched",status="405"`},
+		{"made up method", "X-RANDOM-1234", "/nowhere", `method="other",route="unmatched",status="405"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			var b strings.Builder
+			if _, err := registry.WriteTo(&b); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			if want := "http_requests_total{" + tt.want + "} "; !strings.Contains(b.String(), want) {
+				t.Errorf("metrics do not contain %q:\n%s", want, b.String())
+			}
+			if strings.Contains(b.String(), tt.path) && !strings.Contains(tt.want, tt.path) {
+				t.Errorf("metrics contain the raw path %q", tt.path)
+			}
+		})
+	}
+}
+
+func TestMetricsHandlerRequiresAdminKey(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.NewGaugeFunc("up", "Whether the API is up.", func() float64 { return 1 })
+	h := MetricsHandler(auth.APIKeys{"admin-key": auth.RoleAdmin, "editor-key": auth.RoleEditor}, registry)
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"no key", "", http.StatusUnauthorized},
+		{"unknown key", "guess", http.StatusUnauthorized},
+		{"editor key", "editor-key", http.StatusForbidden},
+		{"admin key", "admin-key", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if leaked := strings.Contains(rec.Body.String(), "# TYPE up gauge"); leaked != (tt.want == http.StatusOK) {
+				t.Errorf("body contains metrics = %v, want %v", leaked, tt.want == http.StatusOK)
+			}
+		})
+	}
+}
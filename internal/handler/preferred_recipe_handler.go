@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type PreferredRecipeHandler struct {
+	preferredRecipeService service.PreferredRecipeService
+}
+
+// NewPreferredRecipeHandler creates a handler for users' preferred recipes.
+func NewPreferredRecipeHandler(preferredRecipeService service.PreferredRecipeService) *PreferredRecipeHandler {
+	return &PreferredRecipeHandler{
+		preferredRecipeService: preferredRecipeService,
+	}
+}
+
+// RegisterPreferredRecipeRoutes sets up the routes for a user's preferred recipes on the provided router.
+// The router is expected to be mounted below a path containing {userID}.
+func (h *PreferredRecipeHandler) RegisterPreferredRecipeRoutes(r chi.Router) {
+	r.MethodFunc(http.MethodGet, "/", h.ListPreferredRecipes)
+	r.MethodFunc(http.MethodPut, "/{itemID}", h.SetPreferredRecipe)
+	r.MethodFunc(http.MethodDelete, "/{itemID}", h.ClearPreferredRecipe)
+}
+
+// --- ListPreferredRecipes ---
+func (h *PreferredRecipeHandler) ListPreferredRecipes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+
+	prefs, err := h.preferredRecipeService.ListPreferredRecipes(ctx, userID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to list preferred recipes", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- SetPreferredRecipe ---
+func (h *PreferredRecipeHandler) SetPreferredRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return
+	}
+
+	var req service.SetPreferredRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	pref, err := h.preferredRecipeService.SetPreferredRecipe(ctx, userID, itemID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrInvalidReference) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "User, item or recipe does not exist", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to set preferred recipe", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(pref); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- ClearPreferredRecipe ---
+func (h *PreferredRecipeHandler) ClearPreferredRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+		return
+	}
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return
+	}
+
+	err = h.preferredRecipeService.ClearPreferredRecipe(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Preferred recipe not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to clear preferred recipe", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- ResolveRecipe ---
+func (h *PreferredRecipeHandler) ResolveRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return
+	}
+
+	var userID *uint64
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		parsed, err := strconv.ParseUint(userIDStr, 10, 64)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, "Invalid user ID format", err)
+			return
+		}
+		if !allowUser(w, r, parsed) {
+			return
+		}
+		userID = &parsed
+	}
+
+	resolved, err := h.preferredRecipeService.ResolveRecipe(ctx, itemID, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Item not found", err)
+		} else if errors.Is(err, service.ErrNoRecipe) {
+			respondWithError(w, r, http.StatusNotFound, "No recipe produces this item", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to resolve recipe", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resolved); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ProjectHandler struct {
+	projectService service.ProjectService
+}
+
+// NewProjectHandler creates a handler for the authenticated user's projects.
+func NewProjectHandler(projectService service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+// RegisterProjectRoutes sets up the routes for projects on the provided router.
+// The router is expected to require an authenticated user, and listHandler to be
+// scoped to that user with ScopeProjectsToUser.
+func (h *ProjectHandler) RegisterProjectRoutes(r chi.Router, listHandler http.HandlerFunc) {
+	r.MethodFunc(http.MethodGet, "/", listHandler)
+	r.MethodFunc(http.MethodPost, "/", h.CreateProject)
+	r.MethodFunc(http.MethodGet, "/{projectID}", h.GetProject)
+	r.MethodFunc(http.MethodPut, "/{projectID}", h.UpdateProject)
+	r.MethodFunc(http.MethodDelete, "/{projectID}", h.DeleteProject)
+	r.MethodFunc(http.MethodGet, "/{projectID}/requirements", h.GetRequirements)
+	r.MethodFunc(http.MethodPut, "/{projectID}/items/{itemID}", h.SetProjectItem)
+	r.MethodFunc(http.MethodPut, "/{projectID}/items/{itemID}/progress", h.UpdateProgress)
+	r.MethodFunc(http.MethodDelete, "/{projectID}/items/{itemID}", h.RemoveProjectItem)
+}
+
+// ScopeProjectsToUser restricts a project list to the authenticated user.
+func ScopeProjectsToUser(r *http.Request, params *pagination.ListParams[domain.ProjectFilters]) error {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		return errors.New("no authenticated user")
+	}
+	params.Filters.UserID = user.ID
+	return nil
+}
+
+// --- CreateProject ---
+func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		respondUnauthorized(w, r, "Authentication required", nil)
+		return
+	}
+
+	var req service.CreateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	project, err := h.projectService.CreateProject(ctx, user.ID, req)
+	if err != nil {
+		respondWithProjectError(w, r, "Failed to create project", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- GetProject ---
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, ok := parseProjectPath(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.GetProjectByID(ctx, userID, projectID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Project not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve project", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- UpdateProject ---
+func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, ok := parseProjectPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(ctx, userID, projectID, req)
+	if err != nil {
+		respondWithProjectError(w, r, "Failed to update project", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- DeleteProject ---
+func (h *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, ok := parseProjectPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.projectService.DeleteProject(ctx, userID, projectID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Project not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete project", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- GetRequirements ---
+func (h *ProjectHandler) GetRequirements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, ok := parseProjectPath(w, r)
+	if !ok {
+		return
+	}
+
+	requirements, err := h.projectService.GetRequirements(ctx, userID, projectID)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipes for a project item form a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Project not found", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to calculate project requirements", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(requirements); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- SetProjectItem ---
+func (h *ProjectHandler) SetProjectItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, itemID, ok := parseProjectItemPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.SetProjectItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	project, err := h.projectService.SetProjectItem(ctx, userID, projectID, itemID, req)
+	if err != nil {
+		respondWithProjectError(w, r, "Failed to set project item", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- UpdateProgress ---
+func (h *ProjectHandler) UpdateProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, itemID, ok := parseProjectItemPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	project, err := h.projectService.UpdateProgress(ctx, userID, projectID, itemID, req)
+	if err != nil {
+		respondWithProjectError(w, r, "Failed to update project progress", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- RemoveProjectItem ---
+func (h *ProjectHandler) RemoveProjectItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, projectID, itemID, ok := parseProjectItemPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.projectService.RemoveProjectItem(ctx, userID, projectID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Project or project item not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to remove project item", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseProjectPath reads the authenticated user and the project ID, responding with an error if either is missing.
+func parseProjectPath(w http.ResponseWriter, r *http.Request) (userID, projectID uint64, ok bool) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		respondUnauthorized(w, r, "Authentication required", nil)
+		return 0, 0, false
+	}
+	projectID, err := strconv.ParseUint(chi.URLParam(r, "projectID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid project ID format", err)
+		return 0, 0, false
+	}
+	return user.ID, projectID, true
+}
+
+// parseProjectItemPath is parseProjectPath for routes that also address one of the project's items.
+func parseProjectItemPath(w http.ResponseWriter, r *http.Request) (userID, projectID, itemID uint64, ok bool) {
+	userID, projectID, ok = parseProjectPath(w, r)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid item ID format", err)
+		return 0, 0, 0, false
+	}
+	return userID, projectID, itemID, true
+}
+
+// respondWithProjectError maps the errors of project writes to responses.
+func respondWithProjectError(w http.ResponseWriter, r *http.Request, failureMessage string, err error) {
+	if errors.Is(err, service.ErrValidation) {
+		respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+	} else if errors.Is(err, storage.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, "Project or project item not found", err)
+	} else if errors.Is(err, storage.ErrDuplicateEntry) {
+		respondWithError(w, r, http.StatusConflict, "A project with this name already exists", err)
+	} else if errors.Is(err, storage.ErrInvalidReference) {
+		respondWithError(w, r, http.StatusUnprocessableEntity, "Item does not exist", err)
+	} else {
+		respondWithError(w, r, http.StatusInternalServerError, failureMessage, err)
+	}
+}
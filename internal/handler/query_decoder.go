@@ -0,0 +1,10 @@
+package handler
+
+import "github.com/gorilla/schema"
+
+// queryDecoder parses query parameters into request structs using their schema tags.
+var queryDecoder = schema.NewDecoder()
+
+func init() {
+	queryDecoder.IgnoreUnknownKeys(true)
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/service"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type RecipeHandler struct {
+	recipeService service.RecipeService
+}
+
+// NewRecipeHandler creates a handler for recipe-related HTTP requests.
+func NewRecipeHandler(recipeService service.RecipeService) *RecipeHandler {
+	return &RecipeHandler{
+		recipeService: recipeService,
+	}
+}
+
+// RegisterRecipeRoutes sets up the routes for recipes on the provided router.
+func (h *RecipeHandler) RegisterRecipeRoutes(r chi.Router, listHandler http.HandlerFunc) {
+	r.MethodFunc(http.MethodGet, "/", listHandler)
+	r.MethodFunc(http.MethodPost, "/", h.CreateRecipe)
+	r.MethodFunc(http.MethodGet, "/cycles", h.ListCycles)
+	r.MethodFunc(http.MethodGet, "/{recipeID}", h.GetRecipeByID)
+	r.MethodFunc(http.MethodGet, "/{recipeID}/yield", h.GetRecipeYield)
+	r.MethodFunc(http.MethodPut, "/{recipeID}", h.UpdateRecipe)
+	r.MethodFunc(http.MethodDelete, "/{recipeID}", h.DeleteRecipe)
+}
+
+// ScopeRecipesToInputItem restricts a recipe list to recipes consuming the item in the route.
+func ScopeRecipesToInputItem(r *http.Request, params *pagination.ListParams[domain.RecipeFilters]) error {
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid item ID format: %w", err)
+	}
+	params.Filters.InputItemID = &itemID
+	return nil
+}
+
+// ScopeRecipesToOutputItem restricts a recipe list to recipes producing the item in the route.
+func ScopeRecipesToOutputItem(r *http.Request, params *pagination.ListParams[domain.RecipeFilters]) error {
+	itemID, err := strconv.ParseUint(chi.URLParam(r, "itemID"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid item ID format: %w", err)
+	}
+	params.Filters.OutputItemID = &itemID
+	return nil
+}
+
+// --- CreateRecipe ---
+func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req service.CreateRecipeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	newRecipe, err := h.recipeService.CreateRecipe(ctx, req)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipe would create a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrInvalidReference) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Recipe references an unknown crafting method or item", err)
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Recipe name already exists", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to create recipe", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newRecipe); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- GetRecipeByID ---
+func (h *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recipeIDStr := chi.URLParam(r, "recipeID")
+	recipeID, err := strconv.ParseUint(recipeIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid recipe ID format", err)
+		return
+	}
+
+	recipe, err := h.recipeService.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Recipe not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve recipe", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(recipe); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- UpdateRecipe ---
+func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recipeIDStr := chi.URLParam(r, "recipeID")
+	recipeID, err := strconv.ParseUint(recipeIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid recipe ID format", err)
+		return
+	}
+
+	var req service.UpdateRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid JSON request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	updatedRecipe, err := h.recipeService.UpdateRecipe(ctx, recipeID, req)
+	if err != nil {
+		if respondWithCycleError(w, r, "Recipe would create a dependency cycle", err) {
+			return
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Recipe not found", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else if errors.Is(err, storage.ErrInvalidReference) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Recipe references an unknown crafting method or item", err)
+		} else if errors.Is(err, storage.ErrDuplicateEntry) {
+			respondWithError(w, r, http.StatusConflict, "Recipe name conflicts with an existing recipe", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to update recipe", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(updatedRecipe); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- ListCycles ---
+func (h *RecipeHandler) ListCycles(w http.ResponseWriter, r *http.Request) {
+	report, err := h.recipeService.ListCycles(r.Context())
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to detect recipe cycles", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- GetRecipeYield ---
+func (h *RecipeHandler) GetRecipeYield(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recipeIDStr := chi.URLParam(r, "recipeID")
+	recipeID, err := strconv.ParseUint(recipeIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid recipe ID format", err)
+		return
+	}
+
+	var req service.YieldRequest
+	if err := queryDecoder.Decode(&req, r.URL.Query()); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	if err := validate.StructCtx(ctx, req); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errDetails := formatValidationErrors(validationErrs)
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, errDetails)
+		} else {
+			respondWithError(w, r, http.StatusBadRequest, "Failed to validate request", err)
+		}
+		return
+	}
+
+	yield, err := h.recipeService.CalculateYield(ctx, recipeID, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Recipe not found", err)
+		} else if errors.Is(err, service.ErrValidation) {
+			respondWithError(w, r, http.StatusUnprocessableEntity, "Validation failed", err, err.Error())
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to calculate recipe yield", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(yield); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to encode successful response", err)
+	}
+}
+
+// --- DeleteRecipe ---
+func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recipeIDStr := chi.URLParam(r, "recipeID")
+	recipeID, err := strconv.ParseUint(recipeIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid recipe ID format", err)
+		return
+	}
+
+	err = h.recipeService.DeleteRecipe(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Recipe not found", err)
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete recipe", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
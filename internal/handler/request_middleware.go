@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/logging"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// requestIDHeader carries the request ID in both directions, so a caller can pass its own
+// ID along and find the request in our logs.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the caller-supplied IDs we accept.
+const maxRequestIDLength = 128
+
+type loggerKey struct{}
+
+// loggerFromContext returns the logger RequestLogger stored in ctx, or the default logger.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// RequestID assigns every request an ID, reusing a well-formed X-Request-ID header or
+// generating one. The ID is echoed in the response and carried by the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts IDs of printable ASCII that are safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // Never fails, see crypto/rand
+	return hex.EncodeToString(b)
+}
+
+// RequestLogger makes logger available to the handlers and logs one line per request once
+// it has been served.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ctx := context.WithValue(r.Context(), loggerKey{}, logger)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK // Nothing was written, which net/http sends as 200
+			}
+			logger.InfoContext(ctx, "Request served",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration", time.Since(start),
+				"remote_addr", r.RemoteAddr,
+			)
+		})
+	}
+}
+
+// Recoverer turns a panic in a handler into a logged 500 response.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Deliberate abort of the response, let net/http handle it
+				panic(rec)
+			}
+			loggerFromContext(r.Context()).ErrorContext(r.Context(), "Panic while serving request",
+				"panic", rec, "stack", string(debug.Stack()))
+			respondWithError(w, r, http.StatusInternalServerError, "Internal Server Error", nil)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
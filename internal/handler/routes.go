@@ -2,11 +2,14 @@ package handler
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/dubbie/calculator-api/internal/auth"
 	"github.com/dubbie/calculator-api/internal/config"
 	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/metrics"
 	"github.com/dubbie/calculator-api/internal/service"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,28 +18,51 @@ import (
 
 func SetupRoutes(
 	cfg config.Config,
+	logger *slog.Logger,
+	metricsRegistry *metrics.Registry,
 	// Item related
 	itemService service.ItemService,
 	itemListService service.ListService[domain.Item, domain.ItemFilters],
 	// Crafting Method related
 	craftingMethodService service.CraftingMethodService,
 	craftingMethodListService service.ListService[domain.CraftingMethod, domain.CraftingMethodFilters],
+	// Recipe related
+	recipeService service.RecipeService,
+	recipeListService service.ListService[domain.Recipe, domain.RecipeFilters],
+	// Calculator
+	calculatorService service.CalculatorService,
+	energyService service.EnergyService,
+	// Preferred recipes
+	preferredRecipeService service.PreferredRecipeService,
+	// Users & authentication
+	userService service.UserService,
+	// Inventories
+	inventoryService service.InventoryService,
+	inventoryListService service.ListService[domain.InventoryItem, domain.InventoryFilters],
+	// Projects
+	projectService service.ProjectService,
+	projectListService service.ListService[domain.Project, domain.ProjectFilters],
+	// Bulk data
+	importService service.ImportService,
+	exportService service.ExportService,
 ) http.Handler {
 	r := chi.NewRouter()
 
 	// CORS Middleware Setup
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-Modified-Since", "If-None-Match", "X-API-Key", "X-CSRF-Token", requestIDHeader},
+		ExposedHeaders:   []string{"ETag", "Link", requestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 
 	// Middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(RequestID)
+	r.Use(RequestLogger(logger))
+	r.Use(RouteMetrics(metricsRegistry))
+	r.Use(Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
 	r.Use(corsMiddleware.Handler)
 
@@ -46,21 +72,100 @@ func SetupRoutes(
 		fmt.Fprintln(w, "OK")
 	})
 
+	// Metrics move to the admin server when it has a port of its own
+	if cfg.MetricsPort == "" {
+		r.Method(http.MethodGet, "/metrics", MetricsHandler(cfg.APIKeys, metricsRegistry))
+	}
+
 	// API
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(Authenticate(userService))
+		r.Use(APIKeyAuth(cfg.APIKeys))
+
+		preferredRecipeHandler := NewPreferredRecipeHandler(preferredRecipeService)
+		usedInListHandler := MakeListHandler(recipeListService, ScopeRecipesToInputItem)
+		producedByListHandler := MakeListHandler(recipeListService, ScopeRecipesToOutputItem)
+
 		// --- Item Routes ---
 		itemHandler := NewItemHandler(itemService)
 		itemListHandler := MakeListHandler(itemListService)
 		r.Route("/items", func(r chi.Router) {
+			r.Use(RequireRoleForWrites(auth.RoleEditor))
 			itemHandler.RegisterItemRoutes(r, itemListHandler)
+			r.MethodFunc(http.MethodGet, "/{itemID}/resolved-recipe", preferredRecipeHandler.ResolveRecipe)
+			r.With(itemHandler.RequireItem).MethodFunc(http.MethodGet, "/{itemID}/used-in", usedInListHandler)
+			r.With(itemHandler.RequireItem).MethodFunc(http.MethodGet, "/{itemID}/produced-by", producedByListHandler)
 		})
 
 		// --- Crafting Method Routes ---
 		craftingMethodHandler := NewCraftingMethodHandler(craftingMethodService)
 		craftingMethodListHandler := MakeListHandler(craftingMethodListService)
 		r.Route("/crafting-methods", func(r chi.Router) {
+			r.Use(RequireRoleForWrites(auth.RoleEditor))
 			craftingMethodHandler.RegisterCraftingMethodRoutes(r, craftingMethodListHandler)
 		})
+
+		// --- Recipe Routes ---
+		recipeHandler := NewRecipeHandler(recipeService)
+		recipeListHandler := MakeListHandler(recipeListService)
+		r.Route("/recipes", func(r chi.Router) {
+			r.Use(RequireRoleForWrites(auth.RoleEditor))
+			recipeHandler.RegisterRecipeRoutes(r, recipeListHandler)
+		})
+
+		// --- Calculator Routes ---
+		calculatorHandler := NewCalculatorHandler(calculatorService)
+		r.Route("/calculate", func(r chi.Router) {
+			calculatorHandler.RegisterCalculatorRoutes(r)
+		})
+
+		// --- Energy Report Routes ---
+		energyHandler := NewEnergyHandler(energyService)
+		r.Route("/energy-report", func(r chi.Router) {
+			energyHandler.RegisterEnergyRoutes(r)
+		})
+
+		// --- Import Routes ---
+		importHandler := NewImportHandler(importService)
+		r.Route("/import", func(r chi.Router) {
+			r.Use(RequireRoleForWrites(auth.RoleEditor))
+			importHandler.RegisterImportRoutes(r)
+		})
+
+		// --- Export Routes ---
+		exportHandler := NewExportHandler(exportService)
+		r.Route("/export", func(r chi.Router) {
+			exportHandler.RegisterExportRoutes(r)
+		})
+
+		// --- Auth Routes ---
+		authHandler := NewAuthHandler(userService)
+		r.Route("/auth", func(r chi.Router) {
+			authHandler.RegisterAuthRoutes(r)
+		})
+		r.With(RequireUser).MethodFunc(http.MethodGet, "/me", authHandler.Me)
+
+		// --- Project Routes ---
+		projectHandler := NewProjectHandler(projectService)
+		projectListHandler := MakeListHandler(projectListService, ScopeProjectsToUser)
+		r.Route("/projects", func(r chi.Router) {
+			r.Use(RequireUser)
+			projectHandler.RegisterProjectRoutes(r, projectListHandler)
+		})
+
+		// --- User Routes ---
+		inventoryHandler := NewInventoryHandler(inventoryService)
+		inventoryListHandler := MakeListHandler(inventoryListService, ScopeInventoryToUser)
+		r.Route("/users/{userID}", func(r chi.Router) {
+			r.Route("/inventory", func(r chi.Router) {
+				r.Use(RequireSelfOrAdmin)
+				inventoryHandler.RegisterInventoryRoutes(r, inventoryListHandler)
+			})
+			r.Route("/preferred-recipes", func(r chi.Router) {
+				r.Use(RequireSelfOrAdmin)
+				preferredRecipeHandler.RegisterPreferredRecipeRoutes(r)
+			})
+		})
 	})
 
 	return r
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/config"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/metrics"
+	"github.com/dubbie/calculator-api/internal/service"
+)
+
+// tokenUsers signs in user 7 with the token "user-7" and rejects every other token.
+type tokenUsers struct {
+	service.UserService
+}
+
+func (tokenUsers) AuthenticateToken(ctx context.Context, token string) (*domain.User, error) {
+	if token != "user-7" {
+		return nil, service.ErrInvalidCredentials
+	}
+	return &domain.User{ID: 7}, nil
+}
+
+func TestSetupRoutesGuardsCatalogWrites(t *testing.T) {
+	cfg := config.Config{APIKeys: auth.APIKeys{"viewer-key": auth.RoleViewer}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	// Every request below must be turned away before it reaches a service, so none is needed
+	h := SetupRoutes(cfg, logger, metrics.NewRegistry(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	writes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/recipes"},
+		{http.MethodPut, "/api/v1/recipes/1"},
+		{http.MethodDelete, "/api/v1/recipes/1"},
+		{http.MethodPost, "/api/v1/items"},
+		{http.MethodDelete, "/api/v1/items/1"},
+		{http.MethodPost, "/api/v1/crafting-methods"},
+		{http.MethodDelete, "/api/v1/crafting-methods/1"},
+	}
+	keys := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"viewer", "viewer-key", http.StatusForbidden},
+	}
+	for _, write := range writes {
+		for _, k := range keys {
+			t.Run(k.name+" "+write.method+" "+write.path, func(t *testing.T) {
+				req := httptest.NewRequest(write.method, write.path, strings.NewReader(`{}`))
+				req.Header.Set("Content-Type", "application/json")
+				if k.key != "" {
+					req.Header.Set("X-API-Key", k.key)
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if rec.Code != k.want {
+					t.Errorf("%s %s = %d, want %d", write.method, write.path, rec.Code, k.want)
+				}
+			})
+		}
+	}
+}
+
+func TestSetupRoutesGuardsPreferences(t *testing.T) {
+	cfg := config.Config{APIKeys: auth.APIKeys{"editor-key": auth.RoleEditor}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := SetupRoutes(cfg, logger, metrics.NewRegistry(), nil, nil, nil, nil, nil, nil, nil, nil, nil, tokenUsers{}, nil, nil, nil, nil, nil, nil)
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/v1/items/1/resolved-recipe?user_id=8", ""},
+		{http.MethodPost, "/api/v1/calculate", `{"item_id":1,"quantity":1,"user_id":8}`},
+		{http.MethodPost, "/api/v1/calculate/shopping-list", `{"item_id":1,"quantity":1,"user_id":8}`},
+		{http.MethodPost, "/api/v1/calculate/throughput", `{"item_id":1,"items_per_minute":1,"user_id":8}`},
+	}
+	callers := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"anonymous", "", "", http.StatusUnauthorized},
+		{"editor key", "X-API-Key", "editor-key", http.StatusUnauthorized},
+		{"other user", "Authorization", "Bearer user-7", http.StatusForbidden},
+	}
+	for _, req := range requests {
+		for _, caller := range callers {
+			t.Run(caller.name+" "+req.path, func(t *testing.T) {
+				r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+				r.Header.Set("Content-Type", "application/json")
+				if caller.header != "" {
+					r.Header.Set(caller.header, caller.value)
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, r)
+				if rec.Code != caller.want {
+					t.Errorf("%s %s = %d, want %d", req.method, req.path, rec.Code, caller.want)
+				}
+			})
+		}
+	}
+}
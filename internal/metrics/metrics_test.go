@@ -0,0 +1,415 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// sample is one parsed sample line of the text exposition format.
+type sample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// family is a parsed metric family: its HELP and TYPE lines and the samples that follow.
+type family struct {
+	help, kind string
+	samples    []sample
+}
+
+// parseExposition parses the text exposition format strictly enough to catch output a
+// Prometheus scraper would reject: every family is introduced by HELP then TYPE, samples
+// belong to the family above them, and label values are quoted and escaped.
+func parseExposition(t *testing.T, text string) map[string]*family {
+	t.Helper()
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		t.Fatalf("exposition does not end with a newline: %q", text)
+	}
+
+	families := map[string]*family{}
+	var current string
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+			name, help, ok := strings.Cut(strings.TrimPrefix(line, "# HELP "), " ")
+			if !ok || !validName(name) {
+				t.Fatalf("line %d: malformed HELP line %q", i+1, line)
+			}
+			if _, seen := families[name]; seen {
+				t.Fatalf("line %d: family %s appears twice", i+1, name)
+			}
+			families[name] = &family{help: unescape(t, help, false)}
+			current = name
+		case strings.HasPrefix(line, "# TYPE "):
+			name, kind, ok := strings.Cut(strings.TrimPrefix(line, "# TYPE "), " ")
+			if !ok || name != current || families[name].kind != "" || len(families[name].samples) > 0 {
+				t.Fatalf("line %d: TYPE line %q does not follow the HELP line of its family", i+1, line)
+			}
+			switch kind {
+			case "counter", "gauge", "histogram", "summary", "untyped":
+			default:
+				t.Fatalf("line %d: unknown metric type %q", i+1, kind)
+			}
+			families[name].kind = kind
+		case strings.HasPrefix(line, "#"):
+			t.Fatalf("line %d: unexpected comment %q", i+1, line)
+		default:
+			s := parseSample(t, line)
+			f := families[current]
+			if f == nil || f.kind == "" || !belongsTo(s.name, current, f.kind) {
+				t.Fatalf("line %d: sample %s outside of its family", i+1, s.name)
+			}
+			f.samples = append(f.samples, s)
+		}
+	}
+	return families
+}
+
+func belongsTo(name, family, kind string) bool {
+	if kind == "histogram" {
+		return name == family+"_bucket" || name == family+"_sum" || name == family+"_count"
+	}
+	return name == family
+}
+
+func parseSample(t *testing.T, line string) sample {
+	t.Helper()
+	s := sample{labels: map[string]string{}}
+
+	end := strings.IndexAny(line, "{ ")
+	if end < 0 {
+		t.Fatalf("malformed sample %q", line)
+	}
+	s.name, line = line[:end], line[end:]
+	if !validName(s.name) {
+		t.Fatalf("invalid metric name %q", s.name)
+	}
+
+	if strings.HasPrefix(line, "{") {
+		line = line[1:]
+		for !strings.HasPrefix(line, "}") {
+			label, rest, ok := strings.Cut(line, `="`)
+			if !ok || !validName(label) || strings.Contains(label, ":") {
+				t.Fatalf("malformed label in %q", line)
+			}
+			// The value ends at the first quote that is not escaped
+			var value strings.Builder
+			for {
+				if rest == "" {
+					t.Fatalf("unterminated label value for %s", label)
+				}
+				if rest[0] == '"' {
+					rest = rest[1:]
+					break
+				}
+				if rest[0] == '\\' && len(rest) > 1 {
+					value.WriteString(rest[:2])
+					rest = rest[2:]
+					continue
+				}
+				value.WriteByte(rest[0])
+				rest = rest[1:]
+			}
+			if _, dup := s.labels[label]; dup {
+				t.Fatalf("label %s repeated", label)
+			}
+			s.labels[label] = unescape(t, value.String(), true)
+			line = strings.TrimPrefix(rest, ",")
+			if line == rest && !strings.HasPrefix(line, "}") {
+				t.Fatalf("expected , or } after label %s, got %q", label, line)
+			}
+		}
+		line = line[1:]
+	}
+
+	if !strings.HasPrefix(line, " ") || strings.Contains(line[1:], " ") {
+		t.Fatalf("expected one space and a value, got %q", line)
+	}
+	value, err := parseFloat(line[1:])
+	if err != nil {
+		t.Fatalf("invalid sample value %q: %v", line[1:], err)
+	}
+	s.value = value
+	return s
+}
+
+func parseFloat(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// unescape undoes the escaping of HELP text or, with quotes, of label values. Raw
+// newlines and, in label values, raw quotes are errors.
+func unescape(t *testing.T, s string, quotes bool) string {
+	t.Helper()
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n' || quotes && c == '"':
+			t.Fatalf("unescaped %q in %q", c, s)
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case '\\':
+				b.WriteByte('\\')
+			case 'n':
+				b.WriteByte('\n')
+			case '"':
+				if !quotes {
+					b.WriteString(`\"`)
+					continue
+				}
+				b.WriteByte('"')
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func exposition(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	n, err := r.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, b.Len())
+	}
+	return b.String()
+}
+
+func TestRegistryWriteTo(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("http_requests_total", "Requests served.", "method", "status")
+	requests.Inc("POST", "201")
+	requests.Inc("GET", "200")
+	requests.Add(2, "GET", "200")
+	durations := r.NewHistogramVec("query_duration_seconds", "Query durations.", []float64{1, 0.1}, "query")
+	durations.Observe(0.05, "list")
+	durations.Observe(0.1, "list")
+	durations.Observe(3, "list")
+	r.NewGaugeFunc("open_connections", "Open connections.", func() float64 { return 4 })
+	r.NewCounterFunc("errors_total", "Errors.", func() float64 { return 0.5 })
+
+	want := `# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",status="200"} 3
+http_requests_total{method="POST",status="201"} 1
+# HELP query_duration_seconds Query durations.
+# TYPE query_duration_seconds histogram
+query_duration_seconds_bucket{query="list",le="0.1"} 2
+query_duration_seconds_bucket{query="list",le="1"} 2
+query_duration_seconds_bucket{query="list",le="+Inf"} 3
+query_duration_seconds_sum{query="list"} 3.15
+query_duration_seconds_count{query="list"} 3
+# HELP open_connections Open connections.
+# TYPE open_connections gauge
+open_connections 4
+# HELP errors_total Errors.
+# TYPE errors_total counter
+errors_total 0.5
+`
+	got := exposition(t, r)
+	if got != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", got, want)
+	}
+	parseExposition(t, got)
+}
+
+func TestRegistryWriteToEscapes(t *testing.T) {
+	tests := []struct {
+		name       string
+		help       string
+		labelValue string
+	}{
+		{"plain", "Plain help.", "/api/v1/items"},
+		{"backslash", `C:\ help`, `C:\path`},
+		{"quote", `say "hi"`, `a "quoted" value`},
+		{"newline", "two\nlines", "two\nlines"},
+		{"all together", "\\\"\n", "\\\"\n"},
+		{"empty value", "", ""},
+		{"utf-8", "Größe", "Größe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.NewCounterVec("escaped_total", tt.help, "value").Inc(tt.labelValue)
+
+			families := parseExposition(t, exposition(t, r))
+			f := families["escaped_total"]
+			if f == nil || len(f.samples) != 1 {
+				t.Fatalf("families = %v, want one escaped_total sample", families)
+			}
+			if f.help != tt.help {
+				t.Errorf("help = %q, want %q", f.help, tt.help)
+			}
+			if got := f.samples[0].labels["value"]; got != tt.labelValue {
+				t.Errorf("label value = %q, want %q", got, tt.labelValue)
+			}
+		})
+	}
+}
+
+func TestHistogramVecBuckets(t *testing.T) {
+	buckets := []float64{0.01, 0.1, 1}
+	tests := []struct {
+		name         string
+		observations []float64
+		want         []float64 // Cumulative counts of the buckets, then +Inf
+	}{
+		{"no observations in range", []float64{5, 10}, []float64{0, 0, 0, 2}},
+		{"upper bounds are inclusive", []float64{0.01, 0.1, 1}, []float64{1, 2, 3, 3}},
+		{"just above a bound", []float64{0.0100001}, []float64{0, 1, 1, 1}},
+		{"negative and zero", []float64{-1, 0}, []float64{2, 2, 2, 2}},
+		{"spread", []float64{0.005, 0.05, 0.5, 5, 50}, []float64{1, 2, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			h := r.NewHistogramVec("duration_seconds", "Durations.", buckets, "route")
+			var sum float64
+			for _, v := range tt.observations {
+				h.Observe(v, "/items")
+				sum += v
+			}
+
+			f := parseExposition(t, exposition(t, r))["duration_seconds"]
+			if f == nil || f.kind != "histogram" {
+				t.Fatalf("duration_seconds family = %+v, want a histogram", f)
+			}
+			var got []float64
+			var gotSum, gotCount float64
+			for _, s := range f.samples {
+				if s.labels["route"] != "/items" {
+					t.Errorf("%s route = %q, want /items", s.name, s.labels["route"])
+				}
+				switch s.name {
+				case "duration_seconds_bucket":
+					want := "+Inf"
+					if len(got) < len(buckets) {
+						want = formatFloat(buckets[len(got)])
+					}
+					if s.labels["le"] != want {
+						t.Errorf("bucket %d le = %q, want %q", len(got), s.labels["le"], want)
+					}
+					got = append(got, s.value)
+				case "duration_seconds_sum":
+					gotSum = s.value
+				case "duration_seconds_count":
+					gotCount = s.value
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("buckets = %v, want %v", got, tt.want)
+			}
+			if gotCount != float64(len(tt.observations)) || gotCount != got[len(got)-1] {
+				t.Errorf("count = %v, want %d and equal to the +Inf bucket %v", gotCount, len(tt.observations), got[len(got)-1])
+			}
+			if math.Abs(gotSum-sum) > 1e-9 {
+				t.Errorf("sum = %v, want %v", gotSum, sum)
+			}
+		})
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{0.25, "0.25"},
+		{1e21, "1e+21"},
+		{-3.5, "-3.5"},
+		{math.Inf(1), "+Inf"},
+		{math.Inf(-1), "-Inf"},
+		{math.NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.in); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{"duplicate name", func(r *Registry) {
+			r.NewCounterVec("dup_total", "First.")
+			r.NewGaugeFunc("dup_total", "Second.", func() float64 { return 0 })
+		}},
+		{"too few label values", func(r *Registry) {
+			r.NewCounterVec("c_total", "Counter.", "method", "status").Inc("GET")
+		}},
+		{"too many label values", func(r *Registry) {
+			r.NewHistogramVec("h_seconds", "Histogram.", DefaultBuckets, "route").Observe(1, "/a", "/b")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeFunc("up", "Whether the API is up.", func() float64 { return 1 })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("Content-Type = %q, want %q", got, contentType)
+	}
+	families := parseExposition(t, rec.Body.String())
+	if f := families["up"]; f == nil || f.kind != "gauge" || len(f.samples) != 1 || f.samples[0].value != 1 {
+		t.Errorf("up family = %+v, want gauge 1", f)
+	}
+}
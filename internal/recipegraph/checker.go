@@ -0,0 +1,95 @@
+package recipegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ErrCycle is matched by every *CycleError.
+var ErrCycle = errors.New("recipe dependency cycle")
+
+// CycleError describes a dependency loop, either one a recipe would close or one
+// found while walking the stored recipes.
+type CycleError struct {
+	// ItemIDs starts and ends with the same item.
+	ItemIDs []uint64
+}
+
+func (e *CycleError) Error() string {
+	ids := make([]string, len(e.ItemIDs))
+	for i, id := range e.ItemIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fmt.Sprintf("%s: %s", ErrCycle, strings.Join(ids, " -> "))
+}
+
+// Is makes errors.Is(err, ErrCycle) match any CycleError.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// Checker validates recipes against the current recipe graph before they are stored.
+type Checker struct {
+	src Source
+}
+
+// NewChecker creates a Checker reading the graph from src.
+func NewChecker(src Source) *Checker {
+	return &Checker{src: src}
+}
+
+// CheckRecipe returns a *CycleError if storing the recipe would make the graph cyclic.
+// Existing edges of the recipe (matched by ID) are ignored, so updates are checked
+// against the graph without their previous version.
+func (c *Checker) CheckRecipe(ctx context.Context, recipe *domain.Recipe) error {
+	edges, err := c.src.ListRecipeEdges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load recipe graph: %w", err)
+	}
+
+	others := edges[:0:0]
+	for _, e := range edges {
+		if recipe.ID == 0 || e.RecipeID != recipe.ID {
+			others = append(others, e)
+		}
+	}
+
+	return New(others).CheckEdges(recipeEdges(recipe))
+}
+
+// CheckEdges returns a *CycleError if adding the edges to the graph would create a cycle.
+// A new edge out -> in closes a loop exactly when out is already reachable from in.
+func (g *Graph) CheckEdges(edges []domain.RecipeEdge) error {
+	for _, e := range edges {
+		if e.InputItemID == e.OutputItemID {
+			return &CycleError{ItemIDs: []uint64{e.OutputItemID, e.InputItemID}}
+		}
+	}
+
+	for _, e := range edges {
+		if path := g.Path(e.InputItemID, e.OutputItemID); path != nil {
+			return &CycleError{ItemIDs: append([]uint64{e.OutputItemID}, path...)}
+		}
+	}
+
+	return nil
+}
+
+// recipeEdges expands a recipe into its (output, input) edges.
+func recipeEdges(recipe *domain.Recipe) []domain.RecipeEdge {
+	edges := make([]domain.RecipeEdge, 0, len(recipe.Outputs)*len(recipe.Inputs))
+	for _, output := range recipe.Outputs {
+		for _, input := range recipe.Inputs {
+			edges = append(edges, domain.RecipeEdge{
+				RecipeID:     recipe.ID,
+				OutputItemID: output.ItemID,
+				InputItemID:  input.InputItemID,
+			})
+		}
+	}
+	return edges
+}
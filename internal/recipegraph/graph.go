@@ -0,0 +1,275 @@
+// Package recipegraph models recipes as a directed graph between items and finds
+// the cycles in it. An edge runs from an item a recipe produces to each item that
+// recipe consumes, so a cycle means an item (indirectly) requires itself.
+package recipegraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// Source provides the recipe edges a Graph is built from.
+// storage.RecipeStore satisfies it.
+type Source interface {
+	ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error)
+}
+
+// Graph is an immutable item dependency graph.
+type Graph struct {
+	// adj maps an output item to the items it depends on. When several recipes link
+	// the same pair of items only the lowest recipe ID is kept.
+	adj   map[uint64]map[uint64]uint64
+	nodes []uint64 // Sorted, for deterministic traversal
+}
+
+// New builds a graph from recipe edges.
+func New(edges []domain.RecipeEdge) *Graph {
+	g := &Graph{adj: make(map[uint64]map[uint64]uint64)}
+	seen := make(map[uint64]bool)
+	for _, e := range edges {
+		g.addEdge(e)
+		for _, id := range []uint64{e.OutputItemID, e.InputItemID} {
+			if !seen[id] {
+				seen[id] = true
+				g.nodes = append(g.nodes, id)
+			}
+		}
+	}
+	sort.Slice(g.nodes, func(i, j int) bool { return g.nodes[i] < g.nodes[j] })
+	return g
+}
+
+// Load builds a graph from all recipes known to the source.
+func Load(ctx context.Context, src Source) (*Graph, error) {
+	edges, err := src.ListRecipeEdges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipe graph: %w", err)
+	}
+	return New(edges), nil
+}
+
+func (g *Graph) addEdge(e domain.RecipeEdge) {
+	targets, ok := g.adj[e.OutputItemID]
+	if !ok {
+		targets = make(map[uint64]uint64)
+		g.adj[e.OutputItemID] = targets
+	}
+	if existing, ok := targets[e.InputItemID]; !ok || e.RecipeID < existing {
+		targets[e.InputItemID] = e.RecipeID
+	}
+}
+
+// successors returns the items the given item depends on, sorted by ID.
+func (g *Graph) successors(id uint64) []uint64 {
+	targets := g.adj[id]
+	result := make([]uint64, 0, len(targets))
+	for to := range targets {
+		result = append(result, to)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// recipeBetween returns the recipe linking two adjacent items.
+func (g *Graph) recipeBetween(from, to uint64) uint64 {
+	return g.adj[from][to]
+}
+
+// StronglyConnectedComponents returns the components that contain at least one cycle:
+// groups of two or more items that all depend on each other, or single items that
+// depend on themselves directly. Items within a component and the components themselves
+// are sorted by item ID.
+func (g *Graph) StronglyConnectedComponents() [][]uint64 {
+	// Tarjan's algorithm
+	var (
+		index    = make(map[uint64]int)
+		lowlink  = make(map[uint64]int)
+		onStack  = make(map[uint64]bool)
+		stack    []uint64
+		next     int
+		comps    [][]uint64
+		connect  func(v uint64)
+		hasCycle = func(comp []uint64) bool {
+			if len(comp) > 1 {
+				return true
+			}
+			_, selfLoop := g.adj[comp[0]][comp[0]]
+			return selfLoop
+		}
+	)
+
+	connect = func(v uint64) {
+		index[v] = next
+		lowlink[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range g.successors(v) {
+			if _, visited := index[w]; !visited {
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var comp []uint64
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			if hasCycle(comp) {
+				sort.Slice(comp, func(i, j int) bool { return comp[i] < comp[j] })
+				comps = append(comps, comp)
+			}
+		}
+	}
+
+	for _, v := range g.nodes {
+		if _, visited := index[v]; !visited {
+			connect(v)
+		}
+	}
+
+	sort.Slice(comps, func(i, j int) bool { return comps[i][0] < comps[j][0] })
+	return comps
+}
+
+// Cycles enumerates the elementary cycles of the graph using Johnson's algorithm,
+// stopping after limit cycles. The returned flag reports whether the limit was hit.
+// Each cycle starts at its lowest item ID.
+func (g *Graph) Cycles(limit int) ([]domain.RecipeCycle, bool) {
+	var cycles []domain.RecipeCycle
+	truncated := false
+
+	for _, comp := range g.StronglyConnectedComponents() {
+		inComp := make(map[uint64]bool, len(comp))
+		for _, v := range comp {
+			inComp[v] = true
+		}
+
+		for _, start := range comp {
+			var (
+				blocked  = make(map[uint64]bool)
+				blockMap = make(map[uint64]map[uint64]bool)
+				stack    []uint64
+				unblock  func(u uint64)
+				circuit  func(v uint64) bool
+			)
+
+			unblock = func(u uint64) {
+				blocked[u] = false
+				for w := range blockMap[u] {
+					delete(blockMap[u], w)
+					if blocked[w] {
+						unblock(w)
+					}
+				}
+			}
+
+			// Only items >= start are considered, so each cycle is found once: from its lowest item.
+			eligible := func(w uint64) bool { return inComp[w] && w >= start }
+
+			circuit = func(v uint64) bool {
+				found := false
+				stack = append(stack, v)
+				blocked[v] = true
+
+				for _, w := range g.successors(v) {
+					if truncated || !eligible(w) {
+						continue
+					}
+					if w == start {
+						if len(cycles) >= limit {
+							truncated = true
+							continue
+						}
+						cycles = append(cycles, g.cycleFromPath(append(append([]uint64{}, stack...), start)))
+						found = true
+					} else if !blocked[w] && circuit(w) {
+						found = true
+					}
+				}
+
+				if found {
+					unblock(v)
+				} else {
+					for _, w := range g.successors(v) {
+						if !eligible(w) {
+							continue
+						}
+						if blockMap[w] == nil {
+							blockMap[w] = make(map[uint64]bool)
+						}
+						blockMap[w][v] = true
+					}
+				}
+
+				stack = stack[:len(stack)-1]
+				return found
+			}
+
+			circuit(start)
+			if truncated {
+				return cycles, true
+			}
+		}
+	}
+
+	return cycles, false
+}
+
+// Path returns the shortest dependency path from one item to another, including both
+// ends, or nil if to can't be reached from from.
+func (g *Graph) Path(from, to uint64) []uint64 {
+	parent := make(map[uint64]uint64)
+	visited := map[uint64]bool{from: true}
+	queue := []uint64{from}
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+
+		if v == to {
+			path := []uint64{v}
+			for v != from {
+				v = parent[v]
+				path = append(path, v)
+			}
+			// Reverse into from -> to order
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+
+		for _, w := range g.successors(v) {
+			if !visited[w] {
+				visited[w] = true
+				parent[w] = v
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	return nil
+}
+
+// cycleFromPath attaches the linking recipes to a closed item path.
+func (g *Graph) cycleFromPath(items []uint64) domain.RecipeCycle {
+	recipes := make([]uint64, 0, len(items)-1)
+	for i := 0; i+1 < len(items); i++ {
+		recipes = append(recipes, g.recipeBetween(items[i], items[i+1]))
+	}
+	return domain.RecipeCycle{ItemIDs: items, RecipeIDs: recipes}
+}
@@ -0,0 +1,239 @@
+package recipegraph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// edge links output to input through recipe.
+func edge(recipe, output, input uint64) domain.RecipeEdge {
+	return domain.RecipeEdge{RecipeID: recipe, OutputItemID: output, InputItemID: input}
+}
+
+// completeGraph links every pair of items 1, 2 and 3 both ways.
+var completeGraph = []domain.RecipeEdge{
+	edge(12, 1, 2), edge(13, 1, 3),
+	edge(21, 2, 1), edge(23, 2, 3),
+	edge(31, 3, 1), edge(32, 3, 2),
+}
+
+func TestGraphCycles(t *testing.T) {
+	tests := []struct {
+		name       string
+		edges      []domain.RecipeEdge
+		wantComps  [][]uint64
+		wantCycles []domain.RecipeCycle
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "chain",
+			edges: []domain.RecipeEdge{edge(1, 1, 2), edge(2, 2, 3)},
+		},
+		{
+			name:  "diamond",
+			edges: []domain.RecipeEdge{edge(1, 1, 2), edge(1, 1, 3), edge(2, 2, 4), edge(3, 3, 4)},
+		},
+		{
+			name:       "self loop",
+			edges:      []domain.RecipeEdge{edge(7, 5, 5), edge(8, 5, 6)},
+			wantComps:  [][]uint64{{5}},
+			wantCycles: []domain.RecipeCycle{{ItemIDs: []uint64{5, 5}, RecipeIDs: []uint64{7}}},
+		},
+		{
+			name:       "two items",
+			edges:      []domain.RecipeEdge{edge(1, 2, 1), edge(2, 1, 2)},
+			wantComps:  [][]uint64{{1, 2}},
+			wantCycles: []domain.RecipeCycle{{ItemIDs: []uint64{1, 2, 1}, RecipeIDs: []uint64{2, 1}}},
+		},
+		{
+			name:       "lowest recipe kept per item pair",
+			edges:      []domain.RecipeEdge{edge(9, 1, 2), edge(4, 1, 2), edge(6, 2, 1)},
+			wantComps:  [][]uint64{{1, 2}},
+			wantCycles: []domain.RecipeCycle{{ItemIDs: []uint64{1, 2, 1}, RecipeIDs: []uint64{4, 6}}},
+		},
+		{
+			name:      "triangle with chord",
+			edges:     []domain.RecipeEdge{edge(1, 1, 2), edge(2, 2, 3), edge(3, 3, 1), edge(4, 2, 1)},
+			wantComps: [][]uint64{{1, 2, 3}},
+			wantCycles: []domain.RecipeCycle{
+				{ItemIDs: []uint64{1, 2, 1}, RecipeIDs: []uint64{1, 4}},
+				{ItemIDs: []uint64{1, 2, 3, 1}, RecipeIDs: []uint64{1, 2, 3}},
+			},
+		},
+		{
+			name:      "complete graph",
+			edges:     completeGraph,
+			wantComps: [][]uint64{{1, 2, 3}},
+			wantCycles: []domain.RecipeCycle{
+				{ItemIDs: []uint64{1, 2, 1}, RecipeIDs: []uint64{12, 21}},
+				{ItemIDs: []uint64{1, 2, 3, 1}, RecipeIDs: []uint64{12, 23, 31}},
+				{ItemIDs: []uint64{1, 3, 1}, RecipeIDs: []uint64{13, 31}},
+				{ItemIDs: []uint64{1, 3, 2, 1}, RecipeIDs: []uint64{13, 32, 21}},
+				{ItemIDs: []uint64{2, 3, 2}, RecipeIDs: []uint64{23, 32}},
+			},
+		},
+		{
+			name: "separate components joined by a bridge",
+			edges: []domain.RecipeEdge{
+				edge(1, 11, 10), edge(2, 10, 11),
+				edge(3, 2, 1), edge(4, 1, 2),
+				edge(5, 2, 10),
+			},
+			wantComps: [][]uint64{{1, 2}, {10, 11}},
+			wantCycles: []domain.RecipeCycle{
+				{ItemIDs: []uint64{1, 2, 1}, RecipeIDs: []uint64{4, 3}},
+				{ItemIDs: []uint64{10, 11, 10}, RecipeIDs: []uint64{2, 1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.edges)
+
+			if got := g.StronglyConnectedComponents(); !reflect.DeepEqual(got, tt.wantComps) {
+				t.Errorf("StronglyConnectedComponents() = %v, want %v", got, tt.wantComps)
+			}
+			cycles, truncated := g.Cycles(100)
+			if !reflect.DeepEqual(cycles, tt.wantCycles) {
+				t.Errorf("Cycles() = %v, want %v", cycles, tt.wantCycles)
+			}
+			if truncated {
+				t.Error("Cycles() truncated, want all cycles")
+			}
+		})
+	}
+}
+
+func TestGraphCyclesLimit(t *testing.T) {
+	tests := []struct {
+		limit         int
+		wantCycles    int
+		wantTruncated bool
+	}{
+		{0, 0, true},
+		{1, 1, true},
+		{4, 4, true},
+		{5, 5, false},
+		{6, 5, false},
+	}
+	g := New(completeGraph)
+	for _, tt := range tests {
+		cycles, truncated := g.Cycles(tt.limit)
+		if len(cycles) != tt.wantCycles || truncated != tt.wantTruncated {
+			t.Errorf("Cycles(%d) = %d cycles, truncated %v, want %d, %v", tt.limit, len(cycles), truncated, tt.wantCycles, tt.wantTruncated)
+		}
+	}
+}
+
+func TestGraphPath(t *testing.T) {
+	g := New([]domain.RecipeEdge{
+		edge(1, 1, 2), edge(2, 2, 3), edge(3, 3, 4),
+		edge(4, 1, 5), edge(5, 5, 4),
+		edge(6, 6, 6),
+	})
+	tests := []struct {
+		name     string
+		from, to uint64
+		want     []uint64
+	}{
+		{"same item", 1, 1, []uint64{1}},
+		{"direct", 1, 2, []uint64{1, 2}},
+		{"shortest of two", 1, 4, []uint64{1, 5, 4}},
+		{"against the edges", 4, 1, nil},
+		{"unknown item", 99, 1, nil},
+		{"self loop", 6, 6, []uint64{6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Path(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Path(%d, %d) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// edgeSource is a Source serving fixed edges.
+type edgeSource struct {
+	edges []domain.RecipeEdge
+	err   error
+}
+
+func (s edgeSource) ListRecipeEdges(context.Context) ([]domain.RecipeEdge, error) {
+	return s.edges, s.err
+}
+
+// recipe builds a recipe producing outputs from inputs.
+func recipe(id uint64, outputs, inputs []uint64) *domain.Recipe {
+	r := &domain.Recipe{ID: id}
+	for _, item := range outputs {
+		r.Outputs = append(r.Outputs, domain.RecipeOutput{ItemID: item})
+	}
+	for _, item := range inputs {
+		r.Inputs = append(r.Inputs, domain.RecipeInput{InputItemID: item})
+	}
+	return r
+}
+
+func TestCheckerCheckRecipe(t *testing.T) {
+	// Item 1 is made from 2, which is made from 3
+	stored := []domain.RecipeEdge{edge(1, 1, 2), edge(2, 2, 3)}
+
+	tests := []struct {
+		name   string
+		recipe *domain.Recipe
+		want   []uint64 // Items of the expected cycle, nil for none
+	}{
+		{"new item on top", recipe(0, []uint64{4}, []uint64{1}), nil},
+		{"new item below", recipe(0, []uint64{3}, []uint64{5}), nil},
+		{"no inputs", recipe(0, []uint64{1}, nil), nil},
+		{"consumes its output", recipe(0, []uint64{5}, []uint64{5}), []uint64{5, 5}},
+		{"closes a direct loop", recipe(0, []uint64{2}, []uint64{1}), []uint64{2, 1, 2}},
+		{"closes a longer loop", recipe(0, []uint64{3}, []uint64{1}), []uint64{3, 1, 2, 3}},
+		{"one of several outputs loops", recipe(0, []uint64{6, 3}, []uint64{7, 2}), []uint64{3, 2, 3}},
+		{"update replaces its own edges", recipe(1, []uint64{2}, []uint64{1}), nil},
+		{"update closing a loop through others", recipe(1, []uint64{3}, []uint64{2}), []uint64{3, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewChecker(edgeSource{edges: stored}).CheckRecipe(context.Background(), tt.recipe)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("CheckRecipe() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var cycleErr *CycleError
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("CheckRecipe() error = %v, want a *CycleError", err)
+			}
+			if !reflect.DeepEqual(cycleErr.ItemIDs, tt.want) {
+				t.Errorf("CycleError.ItemIDs = %v, want %v", cycleErr.ItemIDs, tt.want)
+			}
+			if !errors.Is(err, ErrCycle) {
+				t.Error("errors.Is(err, ErrCycle) = false, want true")
+			}
+		})
+	}
+}
+
+func TestCheckerCheckRecipeSourceError(t *testing.T) {
+	sourceErr := errors.New("connection refused")
+	err := NewChecker(edgeSource{err: sourceErr}).CheckRecipe(context.Background(), recipe(0, []uint64{1}, []uint64{2}))
+	if !errors.Is(err, sourceErr) || errors.Is(err, ErrCycle) {
+		t.Errorf("CheckRecipe() error = %v, want the source error", err)
+	}
+}
+
+func TestCycleErrorError(t *testing.T) {
+	err := &CycleError{ItemIDs: []uint64{3, 1, 2, 3}}
+	if want := "recipe dependency cycle: 3 -> 1 -> 2 -> 3"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
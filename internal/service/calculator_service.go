@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// CalculateRequest defines the payload for calculating the materials needed to craft an item.
+type CalculateRequest struct {
+	ItemID   uint64 `json:"item_id" validate:"required"`
+	Quantity uint64 `json:"quantity" validate:"required,min=1,max=1000000000"`
+	// UserID optionally selects whose preferred recipes are used. Only that user or an admin may set it.
+	UserID *uint64 `json:"user_id" validate:"omitempty,min=1"`
+}
+
+// ShoppingListRequest defines the payload for calculating what is still needed given the player's stock.
+type ShoppingListRequest struct {
+	ItemID   uint64 `json:"item_id" validate:"required"`
+	Quantity uint64 `json:"quantity" validate:"required,min=1,max=1000000000"`
+	// Stock maps item IDs to the quantity the player already owns.
+	Stock map[uint64]uint64 `json:"stock" validate:"max=10000"`
+	// UserID optionally selects whose preferred recipes are used. Only that user or an admin may set it.
+	UserID *uint64 `json:"user_id" validate:"omitempty,min=1"`
+}
+
+// ThroughputRequest defines the payload for planning a production line for an item.
+type ThroughputRequest struct {
+	ItemID         uint64  `json:"item_id" validate:"required"`
+	ItemsPerMinute float64 `json:"items_per_minute" validate:"required,gt=0,max=1000000000"`
+	// UserID optionally selects whose preferred recipes are used. Only that user or an admin may set it.
+	UserID *uint64 `json:"user_id" validate:"omitempty,min=1"`
+}
+
+// CraftableRequest defines the payload for listing what an inventory can craft.
+type CraftableRequest struct {
+	// Inventory maps item IDs to the quantity owned.
+	Inventory map[uint64]uint64 `json:"inventory" validate:"required,min=1,max=10000"`
+	// Depth is how many levels of intermediate crafts to follow; 0 only considers the inventory.
+	Depth uint `json:"depth" validate:"max=5"`
+}
+
+// CalculatorService defines the interface for expanding items into their crafting requirements.
+type CalculatorService interface {
+	Calculate(ctx context.Context, req CalculateRequest) (*domain.CalculationResult, error)
+	ShoppingList(ctx context.Context, req ShoppingListRequest) (*domain.ShoppingList, error)
+	PlanThroughput(ctx context.Context, req ThroughputRequest) (*domain.ThroughputPlan, error)
+	Craftable(ctx context.Context, req CraftableRequest) (*domain.CraftableRecipes, error)
+}
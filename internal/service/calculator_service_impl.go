@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure calculatorServiceImpl implements CalculatorService
+var _ CalculatorService = (*calculatorServiceImpl)(nil)
+
+type calculatorServiceImpl struct {
+	itemStore   storage.ItemStore
+	recipeStore storage.RecipeStore
+	resolver    *recipeResolver
+}
+
+// NewCalculatorService creates a new CalculatorService implementation.
+func NewCalculatorService(
+	itemStore storage.ItemStore,
+	recipeStore storage.RecipeStore,
+	preferredRecipeStore storage.PreferredRecipeStore,
+) CalculatorService {
+	return &calculatorServiceImpl{
+		itemStore:   itemStore,
+		recipeStore: recipeStore,
+		resolver: &recipeResolver{
+			recipeStore:          recipeStore,
+			preferredRecipeStore: preferredRecipeStore,
+		},
+	}
+}
+
+// Calculate expands the requested item down to raw materials.
+// Recipes are picked per item like ResolveRecipe does, honoring the user's preferences.
+// Each recipe is run ceil(needed / output quantity) times and any surplus is
+// reused by later steps before it is reported as a leftover.
+func (s *calculatorServiceImpl) Calculate(ctx context.Context, req CalculateRequest) (*domain.CalculationResult, error) {
+	tree := newCraftingTree(s.itemStore, s.resolver, req.UserID)
+	return s.expand(ctx, tree, req.ItemID, req.Quantity)
+}
+
+// ShoppingList works like Calculate but consumes the player's stock before crafting
+// or gathering anything, including stock of the target item itself.
+func (s *calculatorServiceImpl) ShoppingList(ctx context.Context, req ShoppingListRequest) (*domain.ShoppingList, error) {
+	tree := newCraftingTree(s.itemStore, s.resolver, req.UserID)
+	tree.useStock(req.Stock)
+
+	result, err := s.expand(ctx, tree, req.ItemID, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ShoppingList{
+		CalculationResult: *result,
+		FromStock:         tree.itemQuantities(tree.fromStock),
+	}, nil
+}
+
+// expand runs the tree for the target and collects its result.
+func (s *calculatorServiceImpl) expand(ctx context.Context, tree *craftingTree, itemID, quantity uint64) (*domain.CalculationResult, error) {
+	if err := tree.require(ctx, itemID, quantity); err != nil {
+		return nil, fmt.Errorf("failed to calculate item %d: %w", itemID, err)
+	}
+	if err := tree.loadPoolItems(ctx); err != nil {
+		return nil, fmt.Errorf("failed to calculate item %d: %w", itemID, err)
+	}
+
+	// The target itself isn't an intermediate
+	delete(tree.crafted, itemID)
+
+	return &domain.CalculationResult{
+		ItemID:         itemID,
+		Quantity:       quantity,
+		RawMaterials:   tree.itemQuantities(tree.raw),
+		Intermediates:  tree.itemQuantities(tree.crafted),
+		Crafts:         tree.recipeRuns(),
+		Leftovers:      tree.itemQuantities(tree.pool),
+		MissingRecipes: tree.itemQuantities(tree.missing),
+	}, nil
+}
+
+// PlanThroughput sizes a production line producing the requested items per minute.
+// Recipes are picked per item like Calculate does; chanced outputs count at their
+// expected rate and byproducts are reported but not fed back into the line.
+func (s *calculatorServiceImpl) PlanThroughput(ctx context.Context, req ThroughputRequest) (*domain.ThroughputPlan, error) {
+	planner := newThroughputPlanner(s.itemStore, s.resolver, req.UserID)
+
+	if err := planner.plan(ctx, req.ItemID, req.ItemsPerMinute); err != nil {
+		return nil, fmt.Errorf("failed to plan throughput for item %d: %w", req.ItemID, err)
+	}
+
+	return planner.result(req.ItemID, req.ItemsPerMinute), nil
+}
+
+// Craftable lists the recipes the inventory can run. Each level of depth adds the
+// guaranteed outputs of the recipes found on the previous level, run as often as
+// possible, to what is available. Runs are counted per recipe, so recipes sharing
+// an input each report the runs they could do on their own.
+func (s *calculatorServiceImpl) Craftable(ctx context.Context, req CraftableRequest) (*domain.CraftableRecipes, error) {
+	available := make(map[uint64]uint64, len(req.Inventory))
+	for itemID, quantity := range req.Inventory {
+		if quantity > 0 {
+			available[itemID] = quantity
+		}
+	}
+
+	result := &domain.CraftableRecipes{
+		Depth:   req.Depth,
+		Recipes: []domain.CraftableRecipe{},
+	}
+	found := make(map[uint64]bool)
+
+	for depth := uint(0); depth <= req.Depth; depth++ {
+		itemIDs := make([]uint64, 0, len(available))
+		for itemID := range available {
+			itemIDs = append(itemIDs, itemID)
+		}
+
+		recipes, err := s.recipeStore.ListRecipesCraftableFrom(ctx, itemIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find craftable recipes: %w", err)
+		}
+
+		// Outputs only become available on the next level
+		produced := make(map[uint64]uint64)
+		for _, recipe := range recipes {
+			if found[recipe.ID] {
+				continue
+			}
+			runs := maxRuns(&recipe, available)
+			if runs == 0 {
+				continue
+			}
+			found[recipe.ID] = true
+			result.Recipes = append(result.Recipes, domain.CraftableRecipe{
+				Recipe:  recipe,
+				MaxRuns: runs,
+				Depth:   depth,
+			})
+
+			for _, output := range recipe.Outputs {
+				if output.Chance < domain.ChanceGuaranteed {
+					continue
+				}
+				quantity, ok := checkedMul(runs, uint64(output.Quantity))
+				if !ok {
+					quantity = math.MaxUint64
+				}
+				produced[output.ItemID] = saturatingAdd(produced[output.ItemID], quantity)
+			}
+		}
+
+		if len(produced) == 0 {
+			break // Nothing new can be made on deeper levels
+		}
+		for itemID, quantity := range produced {
+			available[itemID] = saturatingAdd(available[itemID], quantity)
+		}
+	}
+
+	return result, nil
+}
+
+// maxRuns returns how often the recipe can be run from the available items.
+func maxRuns(recipe *domain.Recipe, available map[uint64]uint64) uint64 {
+	if len(recipe.Inputs) == 0 {
+		return 0
+	}
+	runs := uint64(math.MaxUint64)
+	for _, input := range recipe.Inputs {
+		if input.InputQuantity == 0 {
+			continue
+		}
+		runs = min(runs, available[input.InputItemID]/uint64(input.InputQuantity))
+	}
+	return runs
+}
+
+// saturatingAdd adds a and b, capping the sum at math.MaxUint64.
+func saturatingAdd(a, b uint64) uint64 {
+	if !addInto(&a, b) {
+		return math.MaxUint64
+	}
+	return a
+}
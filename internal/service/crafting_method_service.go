@@ -8,12 +8,25 @@ import (
 )
 
 type CreateCraftingMethodRequest struct {
-	Name        string                `json:"name" validate:"required"`
+	Name        string                `json:"name" validate:"required,max=255"`
 	Description domain.JSONNullString `json:"description"`
 }
 
 type UpdateCraftingMethodRequest struct {
-	Name        *string               `json:"name" validate:"required"`
+	Name        *string               `json:"name" validate:"omitempty,min=1,max=255"`
+	Description domain.JSONNullString `json:"description"`
+}
+
+// PatchCraftingMethodRequest is a JSON Merge Patch (RFC 7396) of a crafting method: absent
+// fields are left unchanged, null clears the description and a value replaces a field.
+type PatchCraftingMethodRequest struct {
+	Name        domain.Optional[string]                `json:"name" validate:"omitempty,min=1,max=255"`
+	Description domain.Optional[domain.JSONNullString] `json:"description"`
+}
+
+// PutCraftingMethodRequest defines the full representation of a crafting method stored under a slug.
+type PutCraftingMethodRequest struct {
+	Name        string                `json:"name" validate:"required,max=255"`
 	Description domain.JSONNullString `json:"description"`
 }
 
@@ -23,15 +36,32 @@ type CraftingMethodService interface {
 		req CreateCraftingMethodRequest,
 	) (*domain.CraftingMethod, error)
 
+	// The writes taking ifMatch fail with ErrPreconditionFailed when it lists versions from an
+	// If-Match header and the crafting method currently has none of them.
+
 	UpdateCraftingMethod(
 		ctx context.Context,
-		id uint64, req UpdateCraftingMethodRequest,
+		id uint64, req UpdateCraftingMethodRequest, ifMatch []uint64,
 	) (*domain.CraftingMethod, error)
 
-	DeleteCraftingMethod(ctx context.Context, id uint64) error
+	PatchCraftingMethod(
+		ctx context.Context,
+		id uint64, req PatchCraftingMethodRequest, ifMatch []uint64,
+	) (*domain.CraftingMethod, error)
+
+	DeleteCraftingMethod(ctx context.Context, id uint64, ifMatch []uint64) error
 
 	GetCraftingMethodByID(ctx context.Context, id uint64) (*domain.CraftingMethod, error)
 
+	GetCraftingMethodBySlug(ctx context.Context, slug string) (*domain.CraftingMethod, error)
+
+	// PutCraftingMethodBySlug creates or replaces the crafting method with the slug and
+	// reports whether it was created.
+	PutCraftingMethodBySlug(
+		ctx context.Context,
+		slug string, req PutCraftingMethodRequest, ifMatch []uint64,
+	) (*domain.CraftingMethod, bool, error)
+
 	ListCraftingMethods(
 		ctx context.Context,
 		params pagination.ListParams[domain.CraftingMethodFilters],
@@ -4,8 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
+	"log/slog"
 
 	"github.com/dubbie/calculator-api/internal/app/pagination"
 	"github.com/dubbie/calculator-api/internal/domain"
@@ -17,43 +16,33 @@ var _ ListService[domain.CraftingMethod, domain.CraftingMethodFilters] = (*craft
 
 type craftingMethodServiceImpl struct {
 	craftingMethodStore storage.CraftingMethodStore
+	logger              *slog.Logger
 }
 
-func NewCraftingMethodService(craftingMethodStore storage.CraftingMethodStore) CraftingMethodService {
+func NewCraftingMethodService(craftingMethodStore storage.CraftingMethodStore, logger *slog.Logger) CraftingMethodService {
 	return &craftingMethodServiceImpl{
 		craftingMethodStore: craftingMethodStore,
+		logger:              logger,
 	}
 }
 
-// --- Slug generation helper ---
-var (
-	widgetNonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9]+`)
-	widgetWhitespaceRegex      = regexp.MustCompile(`\s+`)
-)
-
-func generateCraftingMethodSlug(name string) string {
-	slug := strings.ToLower(name)
-	slug = widgetWhitespaceRegex.ReplaceAllString(slug, "-")
-	slug = widgetNonAlphanumericRegex.ReplaceAllString(slug, "")
-	slug = strings.Trim(slug, "-")
-	// Add uniqueness check/suffix if needed by querying storage
-	return slug
-}
-
 // CreateCraftingMethod
 func (s *craftingMethodServiceImpl) CreateCraftingMethod(
 	ctx context.Context,
 	req CreateCraftingMethodRequest,
 ) (*domain.CraftingMethod, error) {
-	slug := generateCraftingMethodSlug(req.Name)
+	methodSlug, err := uniqueSlug(ctx, req.Name, "crafting-method", "", s.craftingMethodStore.ListSlugsWithPrefix)
+	if err != nil {
+		return nil, err
+	}
 
 	// Map request to domain model
 	newMethod := &domain.CraftingMethod{
 		Name:        req.Name,
-		Slug:        slug,
+		Slug:        methodSlug,
 		Description: req.Description,
 	}
-	err := s.craftingMethodStore.CreateCraftingMethod(ctx, newMethod)
+	err = s.craftingMethodStore.CreateCraftingMethod(ctx, newMethod)
 	if err != nil {
 		if errors.Is(err, storage.ErrDuplicateEntry) {
 			return nil, fmt.Errorf("failed to create crafting method: %w", err)
@@ -71,34 +60,65 @@ func (s *craftingMethodServiceImpl) CreateCraftingMethod(
 	// Alternatively, the storage CreateItem could return these.
 	createdItem, err := s.craftingMethodStore.GetCraftingMethodByID(ctx, newMethod.ID)
 	if err != nil {
-		fmt.Printf("WARNING: Failed to fetch crafting method %d immediately after creation: %v\n", newMethod.ID, err)
+		s.logger.WarnContext(ctx, "Failed to fetch crafting method immediately after creation", "crafting_method_id", newMethod.ID, "error", err)
 		return newMethod, nil
 	}
 
 	return createdItem, nil
 }
 
-// UpdateCraftingMethod
+// UpdateCraftingMethod changes the name if set and always replaces the description.
 func (s *craftingMethodServiceImpl) UpdateCraftingMethod(
 	ctx context.Context,
 	id uint64,
 	req UpdateCraftingMethodRequest,
+	ifMatch []uint64,
+) (*domain.CraftingMethod, error) {
+	patch := PatchCraftingMethodRequest{
+		Description: domain.Optional[domain.JSONNullString]{Value: req.Description, Set: true},
+	}
+	if req.Name != nil {
+		patch.Name = domain.Optional[string]{Value: *req.Name, Set: true}
+	}
+	return s.PatchCraftingMethod(ctx, id, patch, ifMatch)
+}
+
+// PatchCraftingMethod applies a merge patch to a crafting method: absent fields are kept, a
+// null description is cleared and a new name moves the method to a slug derived from it.
+func (s *craftingMethodServiceImpl) PatchCraftingMethod(
+	ctx context.Context,
+	id uint64,
+	req PatchCraftingMethodRequest,
+	ifMatch []uint64,
 ) (*domain.CraftingMethod, error) {
+	if req.Name.Null {
+		return nil, fmt.Errorf("%w: name cannot be null", ErrValidation)
+	}
+
 	// 1. Get the existing crafting method
 	existingMethod, err := s.craftingMethodStore.GetCraftingMethodByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch crafting method %d: %w", id, err)
 	}
+	if err := checkIfMatch(ifMatch, existingMethod.Version); err != nil {
+		return nil, err
+	}
 
 	// 2. Merge changes from request into existing crafting method
 	updated := false
-	if req.Name != nil && *req.Name != existingMethod.Name {
-		existingMethod.Name = *req.Name
-		existingMethod.Slug = generateSlug(existingMethod.Name)
+	if name := req.Name.Or(existingMethod.Name); name != existingMethod.Name {
+		methodSlug, err := uniqueSlug(
+			ctx, name, "crafting-method", existingMethod.Slug, s.craftingMethodStore.ListSlugsWithPrefix,
+		)
+		if err != nil {
+			return nil, err
+		}
+		existingMethod.Name = name
+		existingMethod.Slug = methodSlug
 		updated = true
 	}
-	if req.Description != existingMethod.Description {
-		existingMethod.Description = req.Description
+	if description := req.Description.Or(existingMethod.Description); description != existingMethod.Description {
+		existingMethod.Description = description
 		updated = true
 	}
 
@@ -109,6 +129,7 @@ func (s *craftingMethodServiceImpl) UpdateCraftingMethod(
 	// 3. Store the updated item
 	err = s.craftingMethodStore.UpdateCraftingMethod(ctx, existingMethod)
 	if err != nil {
+		err = conflictError(err, ifMatch)
 		if errors.Is(err, storage.ErrDuplicateEntry) {
 			return nil, fmt.Errorf("failed to update crafting method: %w", err)
 		}
@@ -121,7 +142,7 @@ func (s *craftingMethodServiceImpl) UpdateCraftingMethod(
 	// Fetch again to get db generated timestamps
 	updatedMethod, fetchErr := s.craftingMethodStore.GetCraftingMethodByID(ctx, id)
 	if fetchErr != nil {
-		fmt.Printf("WARNING: Failed to fetch crafting method %d immediately after update: %v\n", id, fetchErr)
+		s.logger.WarnContext(ctx, "Failed to fetch crafting method immediately after update", "crafting_method_id", id, "error", fetchErr)
 		return existingMethod, nil
 	}
 
@@ -129,9 +150,23 @@ func (s *craftingMethodServiceImpl) UpdateCraftingMethod(
 }
 
 // --- DeleteCraftingMethod ---
-func (s *craftingMethodServiceImpl) DeleteCraftingMethod(ctx context.Context, id uint64) error {
-	err := s.craftingMethodStore.DeleteCraftingMethod(ctx, id)
+func (s *craftingMethodServiceImpl) DeleteCraftingMethod(ctx context.Context, id uint64, ifMatch []uint64) error {
+	// With If-Match only the checked version may be deleted
+	var version *uint64
+	if len(ifMatch) > 0 {
+		existingMethod, err := s.craftingMethodStore.GetCraftingMethodByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cannot delete crafting method: %w", err)
+		}
+		if err := checkIfMatch(ifMatch, existingMethod.Version); err != nil {
+			return err
+		}
+		version = &existingMethod.Version
+	}
+
+	err := s.craftingMethodStore.DeleteCraftingMethod(ctx, id, version)
 	if err != nil {
+		err = conflictError(err, ifMatch)
 		if errors.Is(err, storage.ErrNotFound) {
 			return fmt.Errorf("cannot delete crafting method: %w", err)
 		}
@@ -155,6 +190,93 @@ func (s *craftingMethodServiceImpl) GetCraftingMethodByID(
 	return method, nil
 }
 
+// GetCraftingMethodBySlug retrieves a crafting method by its slug using the storage layer.
+func (s *craftingMethodServiceImpl) GetCraftingMethodBySlug(
+	ctx context.Context,
+	slug string,
+) (*domain.CraftingMethod, error) {
+	method, err := s.craftingMethodStore.GetCraftingMethodBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("crafting method with slug %q not found: %w", slug, err)
+		}
+		return nil, fmt.Errorf("failed to get crafting method: %w", err)
+	}
+	return method, nil
+}
+
+// PutCraftingMethodBySlug creates the crafting method under the given slug, or replaces
+// the fields of the one already using it. Repeating the same request changes nothing.
+func (s *craftingMethodServiceImpl) PutCraftingMethodBySlug(
+	ctx context.Context,
+	slug string,
+	req PutCraftingMethodRequest,
+	ifMatch []uint64,
+) (*domain.CraftingMethod, bool, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, false, err
+	}
+
+	existingMethod, err := s.craftingMethodStore.GetCraftingMethodBySlug(ctx, slug)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to get crafting method: %w", err)
+	}
+
+	if existingMethod == nil {
+		// If-Match only matches an existing crafting method, so it can't be used to create one
+		if len(ifMatch) > 0 {
+			return nil, false, fmt.Errorf("%w: crafting method %q does not exist", ErrPreconditionFailed, slug)
+		}
+
+		newMethod := &domain.CraftingMethod{
+			Name:        req.Name,
+			Slug:        slug,
+			Description: req.Description,
+		}
+		if err := s.craftingMethodStore.CreateCraftingMethod(ctx, newMethod); err != nil {
+			if errors.Is(err, storage.ErrDuplicateEntry) {
+				return nil, false, fmt.Errorf("failed to create crafting method: %w", err)
+			}
+			return nil, false, fmt.Errorf("failed to store new crafting method: %w", err)
+		}
+
+		createdMethod, err := s.craftingMethodStore.GetCraftingMethodByID(ctx, newMethod.ID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to fetch crafting method immediately after creation", "crafting_method_id", newMethod.ID, "error", err)
+			return newMethod, true, nil
+		}
+		return createdMethod, true, nil
+	}
+
+	if err := checkIfMatch(ifMatch, existingMethod.Version); err != nil {
+		return nil, false, err
+	}
+
+	replacement := *existingMethod
+	replacement.Name = req.Name
+	replacement.Description = req.Description
+
+	// Repeating a PUT must not bump the version, which would change the ETag for nothing
+	if replacement == *existingMethod {
+		return existingMethod, false, nil
+	}
+
+	if err := s.craftingMethodStore.UpdateCraftingMethod(ctx, &replacement); err != nil {
+		err = conflictError(err, ifMatch)
+		if errors.Is(err, storage.ErrDuplicateEntry) {
+			return nil, false, fmt.Errorf("failed to update crafting method: %w", err)
+		}
+		return nil, false, fmt.Errorf("failed to store updated crafting method: %w", err)
+	}
+
+	updatedMethod, err := s.craftingMethodStore.GetCraftingMethodByID(ctx, replacement.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch crafting method immediately after update", "crafting_method_id", replacement.ID, "error", err)
+		return &replacement, false, nil
+	}
+	return updatedMethod, false, nil
+}
+
 // ListCraftingMethods retrieves a paginated list of crafting methods using the storage layer
 // and constructs the PaginatedResponse.
 func (s *craftingMethodServiceImpl) ListCraftingMethods(
@@ -179,3 +301,27 @@ func (s *craftingMethodServiceImpl) ListCraftingMethods(
 func (s *craftingMethodServiceImpl) List(ctx context.Context, params pagination.ListParams[domain.CraftingMethodFilters]) (pagination.PaginatedResponse[domain.CraftingMethod], error) {
 	return s.ListCraftingMethods(ctx, params)
 }
+
+// ListByCursor (Generic Interface)
+func (s *craftingMethodServiceImpl) ListByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.CraftingMethodFilters],
+) (pagination.CursorResponse[domain.CraftingMethod], error) {
+	methods, page, err := s.craftingMethodStore.ListCraftingMethodsByCursor(ctx, params)
+	if err != nil {
+		return pagination.CursorResponse[domain.CraftingMethod]{}, fmt.Errorf("failed to list crafting methods: %w", err)
+	}
+	return pagination.NewCursorResponse(methods, page, params.PerPage), nil
+}
+
+// ListState (Generic Interface)
+func (s *craftingMethodServiceImpl) ListState(
+	ctx context.Context,
+	params pagination.ListParams[domain.CraftingMethodFilters],
+) (pagination.ListState, error) {
+	state, err := s.craftingMethodStore.GetCraftingMethodListState(ctx, params.Filters)
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("failed to summarize crafting methods: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// craftingCatalog caches the items and selected recipes looked up while expanding a
+// crafting plan, so each is loaded at most once per calculation.
+type craftingCatalog struct {
+	itemStore storage.ItemStore
+	resolver  *recipeResolver
+	userID    *uint64 // Whose preferred recipes to use, if any
+
+	items   map[uint64]*domain.Item   // Item cache
+	recipes map[uint64]*domain.Recipe // Selected recipe per output item, nil if none
+}
+
+func newCraftingCatalog(itemStore storage.ItemStore, resolver *recipeResolver, userID *uint64) *craftingCatalog {
+	return &craftingCatalog{
+		itemStore: itemStore,
+		resolver:  resolver,
+		userID:    userID,
+		items:     make(map[uint64]*domain.Item),
+		recipes:   make(map[uint64]*domain.Recipe),
+	}
+}
+
+// craftingTree expands requested items into raw materials by walking recipes recursively.
+// It keeps a pool of surplus outputs so overproduction and byproducts of one branch
+// are consumed before anything new is crafted in another.
+// A craftingTree is built for a single calculation and is not safe for concurrent use.
+type craftingTree struct {
+	*craftingCatalog
+
+	pool      map[uint64]uint64 // Surplus available for reuse
+	stock     map[uint64]uint64 // What the player already owns and hasn't been used yet
+	fromStock map[uint64]uint64 // What was taken from stock
+	raw       map[uint64]uint64
+	crafted   map[uint64]uint64
+	missing   map[uint64]uint64
+	runs      map[uint64]*domain.RecipeRuns // Keyed by recipe ID
+	runsOrder []uint64
+
+	path []uint64 // Items currently being expanded, used to detect cycles
+}
+
+func newCraftingTree(itemStore storage.ItemStore, resolver *recipeResolver, userID *uint64) *craftingTree {
+	return &craftingTree{
+		craftingCatalog: newCraftingCatalog(itemStore, resolver, userID),
+		pool:            make(map[uint64]uint64),
+		stock:           make(map[uint64]uint64),
+		fromStock:       make(map[uint64]uint64),
+		raw:             make(map[uint64]uint64),
+		crafted:         make(map[uint64]uint64),
+		missing:         make(map[uint64]uint64),
+		runs:            make(map[uint64]*domain.RecipeRuns),
+	}
+}
+
+// useStock lets the tree consume items the player already owns. The map is copied.
+func (t *craftingTree) useStock(stock map[uint64]uint64) {
+	for itemID, quantity := range stock {
+		t.stock[itemID] += quantity
+	}
+}
+
+// require expands quantity units of the item, drawing from the surplus pool first
+// and the player's stock second. Whatever stock covers is never expanded, so an
+// owned intermediate cuts off its whole subtree.
+func (t *craftingTree) require(ctx context.Context, itemID, quantity uint64) error {
+	if quantity == 0 {
+		return nil
+	}
+
+	// Reuse surplus from earlier crafts first
+	if available := t.pool[itemID]; available > 0 {
+		used := min(available, quantity)
+		t.pool[itemID] -= used
+		quantity -= used
+		if quantity == 0 {
+			return nil
+		}
+	}
+
+	item, err := t.item(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	if owned := t.stock[itemID]; owned > 0 {
+		used := min(owned, quantity)
+		t.stock[itemID] -= used
+		t.fromStock[itemID] += used
+		quantity -= used
+		if quantity == 0 {
+			return nil
+		}
+	}
+
+	if item.IsRawMaterial {
+		if !addCount(t.raw, itemID, quantity) {
+			return quantityOverflow(itemID)
+		}
+		return nil
+	}
+
+	recipe, err := t.recipe(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if recipe == nil {
+		if !addCount(t.missing, itemID, quantity) {
+			return quantityOverflow(itemID)
+		}
+		return nil
+	}
+
+	if err := checkPath(t.path, itemID); err != nil {
+		return err
+	}
+	t.path = append(t.path, itemID)
+	defer func() { t.path = t.path[:len(t.path)-1] }()
+
+	// ceil(quantity / perRun), written so it can't overflow itself
+	perRun := outputQuantity(recipe, itemID)
+	runs := quantity / perRun
+	if quantity%perRun != 0 {
+		runs++
+	}
+	produced, ok := checkedMul(runs, perRun)
+	if !ok || !t.addRuns(recipe, itemID, runs) || !addCount(t.crafted, itemID, quantity) {
+		return quantityOverflow(itemID)
+	}
+
+	// Everything produced beyond what was asked for goes to the pool, including
+	// guaranteed byproducts. Chanced outputs can't be relied on, so they're skipped.
+	for _, output := range recipe.Outputs {
+		if output.ItemID == itemID {
+			if !addCount(t.pool, itemID, produced-quantity) {
+				return quantityOverflow(itemID)
+			}
+			continue
+		}
+		if output.Chance >= domain.ChanceGuaranteed {
+			byproduct, ok := checkedMul(runs, uint64(output.Quantity))
+			if !ok || !addCount(t.pool, output.ItemID, byproduct) {
+				return quantityOverflow(output.ItemID)
+			}
+		}
+	}
+
+	for _, input := range recipe.Inputs {
+		needed, ok := checkedMul(runs, uint64(input.InputQuantity))
+		if !ok {
+			return quantityOverflow(input.InputItemID)
+		}
+		if err := t.require(ctx, input.InputItemID, needed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// item returns the item with the given ID, loading it on first use.
+func (c *craftingCatalog) item(ctx context.Context, itemID uint64) (*domain.Item, error) {
+	if item, ok := c.items[itemID]; ok {
+		return item, nil
+	}
+	item, err := c.itemStore.GetItemByID(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("item with id %d not found: %w", itemID, err)
+		}
+		return nil, fmt.Errorf("failed to load item %d: %w", itemID, err)
+	}
+	c.items[itemID] = item
+	return item, nil
+}
+
+// recipe returns the recipe used to craft the item, or nil if no recipe produces it.
+func (c *craftingCatalog) recipe(ctx context.Context, itemID uint64) (*domain.Recipe, error) {
+	if recipe, ok := c.recipes[itemID]; ok {
+		return recipe, nil
+	}
+	resolved, err := c.resolver.resolve(ctx, itemID, c.userID)
+	if err != nil {
+		return nil, err
+	}
+	c.recipes[itemID] = resolved.Recipe
+	return resolved.Recipe, nil
+}
+
+// addRuns adds runs of the recipe to the plan, reporting false if its total overflows.
+func (t *craftingTree) addRuns(recipe *domain.Recipe, itemID, runs uint64) bool {
+	entry, ok := t.runs[recipe.ID]
+	if !ok {
+		entry = &domain.RecipeRuns{
+			RecipeID:         recipe.ID,
+			RecipeName:       recipe.Name,
+			CraftingMethodID: recipe.CraftingMethodID,
+			OutputItemID:     itemID,
+		}
+		t.runs[recipe.ID] = entry
+		t.runsOrder = append(t.runsOrder, recipe.ID)
+	}
+	return addInto(&entry.Runs, runs)
+}
+
+// addCount adds v to the item's quantity, reporting false if the sum overflows.
+func addCount(quantities map[uint64]uint64, itemID, v uint64) bool {
+	sum := quantities[itemID]
+	ok := addInto(&sum, v)
+	quantities[itemID] = sum
+	return ok
+}
+
+// quantityOverflow is the error for a plan whose quantities of the item don't fit in a uint64.
+func quantityOverflow(itemID uint64) error {
+	return fmt.Errorf("%w: quantities of item %d overflow", ErrValidation, itemID)
+}
+
+// checkPath returns a *recipegraph.CycleError if itemID is already on the expansion path,
+// i.e. expanding it again would close a loop.
+// Cycle-creating recipes are rejected on write, so this only guards against data
+// written before that check existed or by concurrent writers.
+func checkPath(path []uint64, itemID uint64) error {
+	for i, onPath := range path {
+		if onPath == itemID {
+			ids := make([]uint64, 0, len(path)-i+1)
+			ids = append(ids, path[i:]...)
+			ids = append(ids, itemID)
+			return &recipegraph.CycleError{ItemIDs: ids}
+		}
+	}
+	return nil
+}
+
+// itemQuantities converts an item ID -> quantity map into a slice sorted by item ID,
+// dropping zero quantities.
+func (t *craftingTree) itemQuantities(quantities map[uint64]uint64) []domain.ItemQuantity {
+	result := make([]domain.ItemQuantity, 0, len(quantities))
+	for itemID, quantity := range quantities {
+		if quantity == 0 {
+			continue
+		}
+		entry := domain.ItemQuantity{ItemID: itemID, Quantity: quantity}
+		if item, ok := t.items[itemID]; ok {
+			entry.Name = item.Name
+			entry.Slug = item.Slug
+		}
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ItemID < result[j].ItemID })
+	return result
+}
+
+// loadPoolItems makes sure every item left in the pool has its details cached,
+// since byproducts are never expanded and so never loaded.
+func (t *craftingTree) loadPoolItems(ctx context.Context) error {
+	for itemID, quantity := range t.pool {
+		if quantity == 0 {
+			continue
+		}
+		if _, err := t.item(ctx, itemID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recipeRuns returns the recipe runs in the order recipes were first used.
+func (t *craftingTree) recipeRuns() []domain.RecipeRuns {
+	result := make([]domain.RecipeRuns, 0, len(t.runsOrder))
+	for _, recipeID := range t.runsOrder {
+		result = append(result, *t.runs[recipeID])
+	}
+	return result
+}
+
+// outputQuantity returns how many units of the item one run of the recipe produces.
+func outputQuantity(recipe *domain.Recipe, itemID uint64) uint64 {
+	for _, output := range recipe.Outputs {
+		if output.ItemID == itemID && output.Quantity > 0 {
+			return uint64(output.Quantity)
+		}
+	}
+	return 1
+}
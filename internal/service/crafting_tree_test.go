@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// newTestCraftingTree returns a tree whose catalog is filled in advance like
+// newTestThroughputPlanner's.
+func newTestCraftingTree(recipes map[uint64]*domain.Recipe) *craftingTree {
+	t := newCraftingTree(nil, nil, nil)
+	for _, id := range []uint64{itemCircuit, itemPlate, itemOre, itemGem, itemSlag, itemBoard, itemWire, itemLoopA, itemLoopB, itemUntimed} {
+		t.items[id] = &domain.Item{ID: id, IsRawMaterial: id == itemOre}
+	}
+	for itemID, recipe := range recipes {
+		t.recipes[itemID] = recipe
+	}
+	return t
+}
+
+func TestCraftingTreeOverflow(t *testing.T) {
+	type request struct {
+		itemID   uint64
+		quantity uint64
+	}
+	tests := []struct {
+		name     string
+		recipes  map[uint64]*domain.Recipe // Selected recipe per output item, nil for none
+		requests []request
+		wantRuns map[uint64]uint64 // Runs per recipe, when the plan fits
+	}{
+		{
+			name:     "raw materials add up",
+			requests: []request{{itemOre, math.MaxUint64}, {itemOre, 1}},
+		},
+		{
+			name:     "missing recipes add up",
+			recipes:  map[uint64]*domain.Recipe{itemGem: nil},
+			requests: []request{{itemGem, math.MaxUint64}, {itemGem, 1}},
+		},
+		{
+			name: "largest quantity rounds up to whole runs",
+			recipes: map[uint64]*domain.Recipe{
+				itemWire: {ID: 30, Outputs: []domain.RecipeOutput{{ItemID: itemWire, Quantity: 3, Chance: domain.ChanceGuaranteed}}},
+			},
+			requests: []request{{itemWire, math.MaxUint64}},
+			wantRuns: map[uint64]uint64{30: math.MaxUint64 / 3},
+		},
+		{
+			name: "whole runs produce too much",
+			recipes: map[uint64]*domain.Recipe{
+				itemWire: {ID: 30, Outputs: []domain.RecipeOutput{{ItemID: itemWire, Quantity: 2, Chance: domain.ChanceGuaranteed}}},
+			},
+			requests: []request{{itemWire, math.MaxUint64}},
+		},
+		{
+			name: "runs add up",
+			recipes: map[uint64]*domain.Recipe{
+				itemWire: {ID: 30, Outputs: []domain.RecipeOutput{{ItemID: itemWire, Quantity: 1, Chance: domain.ChanceGuaranteed}}},
+			},
+			requests: []request{{itemWire, math.MaxUint64}, {itemWire, 1}},
+		},
+		{
+			name: "byproducts",
+			recipes: map[uint64]*domain.Recipe{
+				itemPlate: {
+					ID: 20,
+					Outputs: []domain.RecipeOutput{
+						{ItemID: itemPlate, Quantity: 1, Chance: domain.ChanceGuaranteed},
+						{ItemID: itemSlag, Quantity: 2, Chance: domain.ChanceGuaranteed},
+					},
+				},
+			},
+			requests: []request{{itemPlate, math.MaxUint64}},
+		},
+		{
+			name: "inputs",
+			recipes: map[uint64]*domain.Recipe{
+				itemPlate: {
+					ID:      20,
+					Inputs:  []domain.RecipeInput{{InputItemID: itemOre, InputQuantity: 2}},
+					Outputs: []domain.RecipeOutput{{ItemID: itemPlate, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+				},
+			},
+			requests: []request{{itemPlate, math.MaxUint64}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newTestCraftingTree(tt.recipes)
+			var err error
+			for _, req := range tt.requests {
+				if err = tree.require(context.Background(), req.itemID, req.quantity); err != nil {
+					break
+				}
+			}
+
+			if tt.wantRuns == nil {
+				if !errors.Is(err, ErrValidation) {
+					t.Fatalf("require() error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("require() error = %v", err)
+			}
+			gotRuns := map[uint64]uint64{}
+			for _, runs := range tree.recipeRuns() {
+				gotRuns[runs.RecipeID] = runs.Runs
+			}
+			if !reflect.DeepEqual(gotRuns, tt.wantRuns) {
+				t.Errorf("runs = %v, want %v", gotRuns, tt.wantRuns)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// EnergyCraftRequest is one recipe of a crafting plan and how often it runs.
+type EnergyCraftRequest struct {
+	RecipeID uint64 `json:"recipe_id" validate:"required"`
+	Runs     uint64 `json:"runs" validate:"required,min=1,max=1000000000"`
+}
+
+// EnergyReportRequest defines the payload for an energy report of a crafting plan.
+type EnergyReportRequest struct {
+	Crafts []EnergyCraftRequest `json:"crafts" validate:"required,min=1,max=1000,dive"`
+}
+
+// EnergyService defines the interface for totalling the energy use of crafting plans.
+type EnergyService interface {
+	Report(ctx context.Context, req EnergyReportRequest) (*domain.EnergyReport, error)
+}
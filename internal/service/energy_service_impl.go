@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/dubbie/calculator-api/internal/app/voltage"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure energyServiceImpl implements EnergyService
+var _ EnergyService = (*energyServiceImpl)(nil)
+
+type energyServiceImpl struct {
+	recipeStore storage.RecipeStore
+}
+
+// NewEnergyService creates a new EnergyService implementation.
+func NewEnergyService(recipeStore storage.RecipeStore) EnergyService {
+	return &energyServiceImpl{
+		recipeStore: recipeStore,
+	}
+}
+
+// Report totals EU and ticks over the plan's recipes. Runs of a recipe listed more
+// than once are added together, and recipes without energy data count as zero.
+func (s *energyServiceImpl) Report(ctx context.Context, req EnergyReportRequest) (*domain.EnergyReport, error) {
+	// Merge repeated recipes, keeping the order they were first listed in
+	runs := make(map[uint64]uint64, len(req.Crafts))
+	order := make([]uint64, 0, len(req.Crafts))
+	for _, craft := range req.Crafts {
+		if _, seen := runs[craft.RecipeID]; !seen {
+			order = append(order, craft.RecipeID)
+		}
+		total := runs[craft.RecipeID]
+		if !addInto(&total, craft.Runs) {
+			return nil, fmt.Errorf("%w: runs of recipe %d overflow", ErrValidation, craft.RecipeID)
+		}
+		runs[craft.RecipeID] = total
+	}
+
+	report := &domain.EnergyReport{
+		Recipes:           make([]domain.RecipeEnergy, 0, len(order)),
+		ByCraftingMethod:  []domain.CraftingMethodEnergy{},
+		MissingEnergyData: []uint64{},
+	}
+	methods := make(map[uint64]*domain.CraftingMethodEnergy)
+
+	for _, recipeID := range order {
+		recipe, err := s.recipeStore.GetRecipeByID(ctx, recipeID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, fmt.Errorf("%w: recipe %d does not exist", storage.ErrInvalidReference, recipeID)
+			}
+			return nil, fmt.Errorf("failed to load recipe %d: %w", recipeID, err)
+		}
+
+		entry, err := recipeEnergy(recipe, runs[recipeID])
+		if err != nil {
+			return nil, err
+		}
+		report.Recipes = append(report.Recipes, entry)
+		if !recipe.EUPerTick.Valid || !recipe.DurationTicks.Valid {
+			report.MissingEnergyData = append(report.MissingEnergyData, recipeID)
+		}
+
+		method, ok := methods[recipe.CraftingMethodID]
+		if !ok {
+			method = &domain.CraftingMethodEnergy{CraftingMethodID: recipe.CraftingMethodID}
+			methods[recipe.CraftingMethodID] = method
+		}
+		if !addInto(&method.TotalEU, entry.TotalEU) || !addInto(&method.TotalTicks, entry.TotalTicks) ||
+			!addInto(&method.Runs, entry.Runs) ||
+			!addInto(&report.TotalEU, entry.TotalEU) || !addInto(&report.TotalTicks, entry.TotalTicks) {
+			return nil, fmt.Errorf("%w: plan totals overflow", ErrValidation)
+		}
+		method.PeakEUPerTick = max(method.PeakEUPerTick, recipe.EUPerTick.Int64)
+		report.PeakEUPerTick = max(report.PeakEUPerTick, recipe.EUPerTick.Int64)
+	}
+
+	for _, method := range methods {
+		method.TotalSeconds = ticksToSeconds(method.TotalTicks)
+		method.VoltageTier = voltageTier(method.PeakEUPerTick)
+		report.ByCraftingMethod = append(report.ByCraftingMethod, *method)
+	}
+	sort.Slice(report.ByCraftingMethod, func(i, j int) bool {
+		return report.ByCraftingMethod[i].CraftingMethodID < report.ByCraftingMethod[j].CraftingMethodID
+	})
+
+	report.TotalSeconds = ticksToSeconds(report.TotalTicks)
+	report.VoltageTier = voltageTier(report.PeakEUPerTick)
+
+	return report, nil
+}
+
+// recipeEnergy computes the totals for running a recipe the given number of times.
+func recipeEnergy(recipe *domain.Recipe, runs uint64) (domain.RecipeEnergy, error) {
+	entry := domain.RecipeEnergy{
+		RecipeID:         recipe.ID,
+		RecipeName:       recipe.Name,
+		CraftingMethodID: recipe.CraftingMethodID,
+		Runs:             runs,
+		EUPerTick:        recipe.EUPerTick,
+		DurationTicks:    recipe.DurationTicks,
+		VoltageTier:      voltageTier(recipe.EUPerTick.Int64),
+	}
+
+	var ok bool
+	if recipe.DurationTicks.Valid {
+		if entry.TotalTicks, ok = checkedMul(uint64(recipe.DurationTicks.Int64), runs); !ok {
+			return domain.RecipeEnergy{}, fmt.Errorf("%w: total ticks of recipe %d overflow", ErrValidation, recipe.ID)
+		}
+	}
+	if recipe.EUPerTick.Valid {
+		if entry.TotalEU, ok = checkedMul(uint64(recipe.EUPerTick.Int64), entry.TotalTicks); !ok {
+			return domain.RecipeEnergy{}, fmt.Errorf("%w: total EU of recipe %d overflows", ErrValidation, recipe.ID)
+		}
+	}
+	entry.TotalSeconds = ticksToSeconds(entry.TotalTicks)
+
+	return entry, nil
+}
+
+// voltageTier names the lowest tier supplying euPerTick, or null if none applies.
+func voltageTier(euPerTick int64) domain.JSONNullString {
+	tier, ok := voltage.Classify(euPerTick)
+	return domain.JSONNullString{NullString: sql.NullString{String: tier.Name, Valid: ok}}
+}
+
+func ticksToSeconds(ticks uint64) float64 {
+	return float64(ticks) / domain.TicksPerSecond
+}
+
+// addInto adds v to *dst, reporting false if the sum overflows.
+func addInto(dst *uint64, v uint64) bool {
+	sum, carry := bits.Add64(*dst, v, 0)
+	*dst = sum
+	return carry == 0
+}
+
+// checkedMul multiplies a and b, reporting false if the product overflows.
+func checkedMul(a, b uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	return lo, hi == 0
+}
@@ -0,0 +1,18 @@
+package service
+
+import "errors"
+
+// ErrValidation is returned when a request is well-formed but violates a business rule
+// that can't be expressed through struct validation tags.
+var ErrValidation = errors.New("validation failed")
+
+// ErrNoRecipe is returned when an item exists but no recipe produces it.
+var ErrNoRecipe = errors.New("no recipe produces the item")
+
+// ErrInvalidCredentials is returned when a login's email or password doesn't match,
+// or when a bearer token is invalid, expired or belongs to a deleted user.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrPreconditionFailed is returned when a write's If-Match versions don't include the
+// current version of the resource.
+var ErrPreconditionFailed = errors.New("precondition failed")
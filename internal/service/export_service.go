@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ExportService defines the interface for exporting the game dataset.
+type ExportService interface {
+	// Export returns every crafting method, item and recipe in a canonical order, so
+	// exporting unchanged data always gives the same result.
+	Export(ctx context.Context) (*domain.Dataset, error)
+}
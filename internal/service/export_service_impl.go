@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure exportServiceImpl implements ExportService
+var _ ExportService = (*exportServiceImpl)(nil)
+
+type exportServiceImpl struct {
+	exportStore storage.ExportStore
+}
+
+// NewExportService creates a new ExportService implementation.
+func NewExportService(exportStore storage.ExportStore) ExportService {
+	return &exportServiceImpl{
+		exportStore: exportStore,
+	}
+}
+
+// Export sorts crafting methods and items by slug. Recipe inputs are sorted by item
+// slug and outputs put the primary output first, then sort by slug. Named recipes
+// come first, by name; unnamed ones follow, ordered by crafting method, primary
+// output and finally their full content, so database IDs never affect the order.
+func (s *exportServiceImpl) Export(ctx context.Context) (*domain.Dataset, error) {
+	ds, err := s.exportStore.ExportDataset(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export dataset: %w", err)
+	}
+
+	sort.Slice(ds.CraftingMethods, func(i, j int) bool {
+		return ds.CraftingMethods[i].Slug < ds.CraftingMethods[j].Slug
+	})
+	sort.Slice(ds.Items, func(i, j int) bool {
+		return ds.Items[i].Slug < ds.Items[j].Slug
+	})
+
+	// The full content breaks ties, so it's encoded once the parts are in order
+	contentKeys := make([]string, len(ds.Recipes))
+	for i := range ds.Recipes {
+		recipe := &ds.Recipes[i]
+		sort.Slice(recipe.Inputs, func(a, b int) bool {
+			return recipe.Inputs[a].Item < recipe.Inputs[b].Item
+		})
+		sort.Slice(recipe.Outputs, func(a, b int) bool {
+			if recipe.Outputs[a].IsPrimaryOutput != recipe.Outputs[b].IsPrimaryOutput {
+				return recipe.Outputs[a].IsPrimaryOutput
+			}
+			return recipe.Outputs[a].Item < recipe.Outputs[b].Item
+		})
+
+		content, err := json.Marshal(recipe)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode recipe for sorting: %w", err)
+		}
+		contentKeys[i] = string(content)
+	}
+
+	order := make([]int, len(ds.Recipes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ra, rb := &ds.Recipes[order[a]], &ds.Recipes[order[b]]
+		if ra.Name.Valid != rb.Name.Valid {
+			return ra.Name.Valid
+		}
+		if ra.Name.String != rb.Name.String {
+			return ra.Name.String < rb.Name.String
+		}
+		if ra.CraftingMethod != rb.CraftingMethod {
+			return ra.CraftingMethod < rb.CraftingMethod
+		}
+		if pa, pb := primaryOutputSlug(ra), primaryOutputSlug(rb); pa != pb {
+			return pa < pb
+		}
+		return contentKeys[order[a]] < contentKeys[order[b]]
+	})
+
+	recipes := make([]domain.DatasetRecipe, len(order))
+	for i, idx := range order {
+		recipes[i] = ds.Recipes[idx]
+	}
+	ds.Recipes = recipes
+
+	return ds, nil
+}
+
+// primaryOutputSlug returns the slug of the recipe's primary output, or "" if it has none.
+func primaryOutputSlug(recipe *domain.DatasetRecipe) string {
+	for _, output := range recipe.Outputs {
+		if output.IsPrimaryOutput {
+			return output.Item
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ImportRequest defines a bulk import of game data.
+type ImportRequest struct {
+	Dataset domain.Dataset
+	Mode    domain.ImportMode // Defaults to domain.ImportAtomic
+}
+
+// ImportService defines the interface for bulk imports of items, crafting methods and recipes.
+type ImportService interface {
+	// Import upserts crafting methods and items by slug and recipes by name, in that
+	// order, so rows can reference rows created earlier in the same import. Row
+	// failures are reported rather than returned; an error means the import itself failed.
+	Import(ctx context.Context, req ImportRequest) (*domain.ImportReport, error)
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/app/slug"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-playground/validator/v10"
+)
+
+// Ensure importServiceImpl implements ImportService
+var _ ImportService = (*importServiceImpl)(nil)
+
+// maxImportNameLength matches the VARCHAR(255) recipe name column.
+const maxImportNameLength = 255
+
+// errImportRolledBack makes an atomic import with failed rows roll back.
+var errImportRolledBack = errors.New("import rolled back")
+
+type importServiceImpl struct {
+	importStore storage.ImportStore
+	validate    *validator.Validate
+}
+
+// NewImportService creates a new ImportService implementation.
+// Items and crafting methods are validated like the API requests creating them.
+func NewImportService(importStore storage.ImportStore) ImportService {
+	return &importServiceImpl{
+		importStore: importStore,
+		validate:    NewValidator(),
+	}
+}
+
+// Import writes the dataset in one transaction. Every row runs in its own savepoint,
+// so a failed row leaves nothing behind and the remaining rows are still tried; in
+// atomic mode the transaction is then rolled back, in skip-invalid mode it's committed.
+func (s *importServiceImpl) Import(ctx context.Context, req ImportRequest) (*domain.ImportReport, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = domain.ImportAtomic
+	}
+	if mode != domain.ImportAtomic && mode != domain.ImportSkipInvalid {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", ErrValidation, domain.ImportAtomic, domain.ImportSkipInvalid)
+	}
+
+	report := &domain.ImportReport{
+		Mode: mode,
+		Rows: []domain.ImportRowResult{},
+	}
+
+	err := s.importStore.RunImport(ctx, func(tx storage.ImportTx) error {
+		run := &importRun{
+			tx:                tx,
+			validate:          s.validate,
+			report:            report,
+			craftingMethodIDs: make(map[string]uint64),
+			itemIDs:           make(map[string]uint64),
+		}
+		if err := run.importDataset(ctx, req.Dataset); err != nil {
+			return err
+		}
+		if report.Failed > 0 && mode == domain.ImportAtomic {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, fmt.Errorf("failed to import dataset: %w", err)
+	}
+
+	report.Committed = err == nil
+	return report, nil
+}
+
+// importRun holds the state of one import transaction.
+type importRun struct {
+	tx       storage.ImportTx
+	validate *validator.Validate
+	report   *domain.ImportReport
+
+	// Slug to ID caches, only holding rows that were written or found successfully
+	craftingMethodIDs map[string]uint64
+	itemIDs           map[string]uint64
+	// edges is the recipe graph as of the last successful recipe row, loaded on first use
+	edges recipeEdgeList
+}
+
+func (r *importRun) importDataset(ctx context.Context, ds domain.Dataset) error {
+	for i, row := range ds.CraftingMethods {
+		if err := r.importCraftingMethod(ctx, i+1, row); err != nil {
+			return err
+		}
+	}
+	for i, row := range ds.Items {
+		if err := r.importItem(ctx, i+1, row); err != nil {
+			return err
+		}
+	}
+	for i, row := range ds.Recipes {
+		if err := r.importRecipe(ctx, i+1, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *importRun) importCraftingMethod(ctx context.Context, n int, row domain.DatasetCraftingMethod) error {
+	methodSlug := row.Slug
+	if methodSlug == "" {
+		methodSlug = slug.Make(row.Name)
+	}
+
+	var method *domain.CraftingMethod
+	ok, err := r.writeRow(ctx, domain.ImportKindCraftingMethod, n, methodSlug, func() (uint64, domain.ImportRowStatus, error) {
+		req := CreateCraftingMethodRequest{Name: row.Name, Description: row.Description}
+		if err := r.validateRow(ctx, req, methodSlug); err != nil {
+			return 0, "", err
+		}
+
+		existing, err := r.tx.GetCraftingMethodBySlug(ctx, methodSlug)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return 0, "", err
+		}
+		status := domain.ImportRowUpdated
+		if existing == nil {
+			existing = &domain.CraftingMethod{Slug: methodSlug}
+			status = domain.ImportRowCreated
+		}
+
+		method = existing
+		method.Name = row.Name
+		method.Description = row.Description
+		if status == domain.ImportRowCreated {
+			err = r.tx.CreateCraftingMethod(ctx, method)
+		} else {
+			err = r.tx.UpdateCraftingMethod(ctx, method)
+		}
+		return method.ID, status, err
+	})
+	if ok {
+		r.craftingMethodIDs[methodSlug] = method.ID
+	}
+	return err
+}
+
+func (r *importRun) importItem(ctx context.Context, n int, row domain.DatasetItem) error {
+	itemSlug := row.Slug
+	if itemSlug == "" {
+		itemSlug = slug.Make(row.Name)
+	}
+
+	var item *domain.Item
+	ok, err := r.writeRow(ctx, domain.ImportKindItem, n, itemSlug, func() (uint64, domain.ImportRowStatus, error) {
+		req := CreateItemRequest{
+			Name:          row.Name,
+			IsRawMaterial: row.IsRawMaterial,
+			Description:   row.Description,
+			ImageURL:      row.ImageURL,
+		}
+		if err := r.validateRow(ctx, req, itemSlug); err != nil {
+			return 0, "", err
+		}
+
+		existing, err := r.tx.GetItemBySlug(ctx, itemSlug)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return 0, "", err
+		}
+		status := domain.ImportRowUpdated
+		if existing == nil {
+			existing = &domain.Item{Slug: itemSlug}
+			status = domain.ImportRowCreated
+		}
+
+		item = existing
+		item.Name = row.Name
+		item.IsRawMaterial = row.IsRawMaterial
+		item.Description = row.Description
+		item.ImageURL = row.ImageURL
+		if status == domain.ImportRowCreated {
+			err = r.tx.CreateItem(ctx, item)
+		} else {
+			err = r.tx.UpdateItem(ctx, item)
+		}
+		return item.ID, status, err
+	})
+	if ok {
+		r.itemIDs[itemSlug] = item.ID
+	}
+	return err
+}
+
+func (r *importRun) importRecipe(ctx context.Context, n int, row domain.DatasetRecipe) error {
+	if r.edges == nil {
+		edges, err := r.tx.ListRecipeEdges(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load recipe graph: %w", err)
+		}
+		r.edges = edges
+	}
+
+	var recipe *domain.Recipe
+	ok, err := r.writeRow(ctx, domain.ImportKindRecipe, n, row.Name.String, func() (uint64, domain.ImportRowStatus, error) {
+		var err error
+		recipe, err = r.buildRecipe(ctx, row)
+		if err != nil {
+			return 0, "", err
+		}
+
+		status := domain.ImportRowCreated
+		if recipe.Name.Valid {
+			id, err := r.tx.GetRecipeIDByName(ctx, recipe.Name.String)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return 0, "", err
+			}
+			if err == nil {
+				recipe.ID = id
+				status = domain.ImportRowUpdated
+			}
+		}
+
+		if err := recipegraph.NewChecker(r.edges).CheckRecipe(ctx, recipe); err != nil {
+			return 0, "", err
+		}
+
+		if status == domain.ImportRowCreated {
+			err = r.tx.CreateRecipe(ctx, recipe)
+		} else {
+			err = r.tx.UpdateRecipe(ctx, recipe)
+		}
+		return recipe.ID, status, err
+	})
+	if ok {
+		r.edges = r.edges.replace(recipe)
+	}
+	return err
+}
+
+// buildRecipe validates a recipe row and resolves its slugs.
+func (r *importRun) buildRecipe(ctx context.Context, row domain.DatasetRecipe) (*domain.Recipe, error) {
+	name := row.Name
+	if name.Valid && name.String == "" {
+		name.Valid = false
+	}
+	if name.Valid && len(name.String) > maxImportNameLength {
+		return nil, fmt.Errorf("%w: name must be at most %d characters", ErrValidation, maxImportNameLength)
+	}
+	if row.CraftingMethod == "" {
+		return nil, fmt.Errorf("%w: crafting_method is required", ErrValidation)
+	}
+	if err := validateRecipeEnergy(row.EUPerTick, row.DurationTicks); err != nil {
+		return nil, err
+	}
+
+	craftingMethodID, err := r.craftingMethodID(ctx, row.CraftingMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	inputReqs := make([]RecipeInputRequest, 0, len(row.Inputs))
+	for _, input := range row.Inputs {
+		if input.Quantity == 0 {
+			return nil, fmt.Errorf("%w: input %q needs a quantity of at least 1", ErrValidation, input.Item)
+		}
+		itemID, err := r.itemID(ctx, input.Item)
+		if err != nil {
+			return nil, err
+		}
+		inputReqs = append(inputReqs, RecipeInputRequest{ItemID: itemID, Quantity: input.Quantity})
+	}
+
+	outputReqs := make([]RecipeOutputRequest, 0, len(row.Outputs))
+	for _, output := range row.Outputs {
+		if output.Chance > domain.ChanceGuaranteed {
+			return nil, fmt.Errorf("%w: chance of output %q must be at most %d", ErrValidation, output.Item, domain.ChanceGuaranteed)
+		}
+		itemID, err := r.itemID(ctx, output.Item)
+		if err != nil {
+			return nil, err
+		}
+		outputReq := RecipeOutputRequest{
+			ItemID:          itemID,
+			Quantity:        output.Quantity,
+			IsPrimaryOutput: output.IsPrimaryOutput,
+		}
+		if output.Chance > 0 {
+			outputReq.Chance = &output.Chance
+		}
+		outputReqs = append(outputReqs, outputReq)
+	}
+
+	inputs, err := buildRecipeInputs(inputReqs)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := buildRecipeOutputs(outputReqs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Recipe{
+		Name:             name,
+		CraftingMethodID: craftingMethodID,
+		EUPerTick:        row.EUPerTick,
+		DurationTicks:    row.DurationTicks,
+		Notes:            row.Notes,
+		IsDefault:        row.IsDefault,
+		Inputs:           inputs,
+		Outputs:          outputs,
+	}, nil
+}
+
+func (r *importRun) craftingMethodID(ctx context.Context, slug string) (uint64, error) {
+	if id, ok := r.craftingMethodIDs[slug]; ok {
+		return id, nil
+	}
+	method, err := r.tx.GetCraftingMethodBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, fmt.Errorf("%w: crafting method %q does not exist", storage.ErrInvalidReference, slug)
+		}
+		return 0, err
+	}
+	r.craftingMethodIDs[slug] = method.ID
+	return method.ID, nil
+}
+
+func (r *importRun) itemID(ctx context.Context, slug string) (uint64, error) {
+	if id, ok := r.itemIDs[slug]; ok {
+		return id, nil
+	}
+	item, err := r.tx.GetItemBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, fmt.Errorf("%w: item %q does not exist", storage.ErrInvalidReference, slug)
+		}
+		return 0, err
+	}
+	r.itemIDs[slug] = item.ID
+	return item.ID, nil
+}
+
+// writeRow runs write in a savepoint and records the outcome in the report. It reports
+// whether the row was written; only errors that aren't the row's fault are returned.
+func (r *importRun) writeRow(
+	ctx context.Context,
+	kind string,
+	n int,
+	key string,
+	write func() (uint64, domain.ImportRowStatus, error),
+) (bool, error) {
+	var (
+		id     uint64
+		status domain.ImportRowStatus
+	)
+	err := r.tx.Savepoint(ctx, func() error {
+		var err error
+		id, status, err = write()
+		return err
+	})
+
+	result := domain.ImportRowResult{Kind: kind, Row: n, Key: key}
+	switch {
+	case err == nil:
+		result.ID = id
+		result.Status = status
+		if status == domain.ImportRowCreated {
+			r.report.Created++
+		} else {
+			r.report.Updated++
+		}
+	case isImportRowError(err):
+		result.Status = domain.ImportRowFailed
+		result.Error = err.Error()
+		r.report.Failed++
+	default:
+		return false, fmt.Errorf("%s row %d: %w", kind, n, err)
+	}
+
+	r.report.Rows = append(r.report.Rows, result)
+	return err == nil, nil
+}
+
+// isImportRowError reports whether err is caused by the row's data.
+func isImportRowError(err error) bool {
+	return errors.Is(err, ErrValidation) ||
+		errors.Is(err, storage.ErrDuplicateEntry) ||
+		errors.Is(err, storage.ErrInvalidReference) ||
+		errors.Is(err, recipegraph.ErrCycle)
+}
+
+// validateRow checks an imported item or crafting method as the API request creating it,
+// and its slug.
+func (r *importRun) validateRow(ctx context.Context, req any, slug string) error {
+	if err := r.validate.StructCtx(ctx, req); err != nil {
+		return validationError(err)
+	}
+	return validateSlug(slug)
+}
+
+// recipeEdgeList is an in-memory recipegraph.Source.
+type recipeEdgeList []domain.RecipeEdge
+
+func (l recipeEdgeList) ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error) {
+	return l, nil
+}
+
+// replace returns the list with the recipe's edges swapped for its current ones.
+func (l recipeEdgeList) replace(recipe *domain.Recipe) recipeEdgeList {
+	edges := l[:0]
+	for _, e := range l {
+		if e.RecipeID != recipe.ID {
+			edges = append(edges, e)
+		}
+	}
+	for _, output := range recipe.Outputs {
+		for _, input := range recipe.Inputs {
+			edges = append(edges, domain.RecipeEdge{
+				RecipeID:     recipe.ID,
+				OutputItemID: output.ItemID,
+				InputItemID:  input.InputItemID,
+			})
+		}
+	}
+	return edges
+}
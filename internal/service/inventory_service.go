@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// SetInventoryQuantityRequest defines the payload for replacing the quantity of an item.
+type SetInventoryQuantityRequest struct {
+	Quantity *uint64 `json:"quantity" validate:"required,max=1000000000000"`
+}
+
+// ChangeInventoryQuantityRequest defines the payload for adding or removing an amount of an item.
+type ChangeInventoryQuantityRequest struct {
+	Quantity uint64 `json:"quantity" validate:"required,min=1,max=1000000000000"`
+}
+
+// InventoryAdjustmentRequest changes the quantity of one item by a signed amount.
+type InventoryAdjustmentRequest struct {
+	ItemID uint64 `json:"item_id" validate:"required"`
+	Delta  int64  `json:"delta" validate:"required,min=-1000000000000,max=1000000000000"`
+}
+
+// AdjustInventoryRequest defines the payload for applying many adjustments at once.
+type AdjustInventoryRequest struct {
+	Adjustments []InventoryAdjustmentRequest `json:"adjustments" validate:"required,min=1,max=1000,dive"`
+}
+
+// InventoryService defines the interface for managing users' inventories.
+type InventoryService interface {
+	GetInventoryItem(ctx context.Context, userID, itemID uint64) (*domain.InventoryItem, error)
+	SetQuantity(ctx context.Context, userID, itemID uint64, req SetInventoryQuantityRequest) (*domain.InventoryItem, error)
+	AddQuantity(ctx context.Context, userID, itemID uint64, req ChangeInventoryQuantityRequest) (*domain.InventoryItem, error)
+	RemoveQuantity(ctx context.Context, userID, itemID uint64, req ChangeInventoryQuantityRequest) (*domain.InventoryItem, error)
+	// AdjustInventory applies every adjustment or none and returns the resulting entries.
+	AdjustInventory(ctx context.Context, userID uint64, req AdjustInventoryRequest) ([]domain.InventoryItem, error)
+	DeleteInventoryItem(ctx context.Context, userID, itemID uint64) error
+	ListInventory(ctx context.Context, params pagination.ListParams[domain.InventoryFilters]) (pagination.PaginatedResponse[domain.InventoryItem], error)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure inventoryServiceImpl implements InventoryService
+var _ InventoryService = (*inventoryServiceImpl)(nil)
+
+// Ensure inventoryServiceImpl implements the generic ListService for inventories
+var _ ListService[domain.InventoryItem, domain.InventoryFilters] = (*inventoryServiceImpl)(nil)
+
+type inventoryServiceImpl struct {
+	inventoryStore storage.InventoryStore
+}
+
+// NewInventoryService creates a new InventoryService implementation.
+func NewInventoryService(inventoryStore storage.InventoryStore) InventoryService {
+	return &inventoryServiceImpl{
+		inventoryStore: inventoryStore,
+	}
+}
+
+// GetInventoryItem retrieves the user's quantity of an item.
+func (s *inventoryServiceImpl) GetInventoryItem(ctx context.Context, userID, itemID uint64) (*domain.InventoryItem, error) {
+	entry, err := s.inventoryStore.GetInventoryItem(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("item %d not in inventory of user %d: %w", itemID, userID, err)
+		}
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	return entry, nil
+}
+
+// --- SetQuantity ---
+func (s *inventoryServiceImpl) SetQuantity(ctx context.Context, userID, itemID uint64, req SetInventoryQuantityRequest) (*domain.InventoryItem, error) {
+	err := s.inventoryStore.SetInventoryQuantity(ctx, userID, itemID, *req.Quantity)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to set inventory quantity: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store inventory quantity: %w", err)
+	}
+
+	return s.fetchAfterWrite(ctx, userID, itemID)
+}
+
+// --- AddQuantity ---
+func (s *inventoryServiceImpl) AddQuantity(ctx context.Context, userID, itemID uint64, req ChangeInventoryQuantityRequest) (*domain.InventoryItem, error) {
+	return s.changeQuantity(ctx, userID, itemID, int64(req.Quantity))
+}
+
+// --- RemoveQuantity ---
+func (s *inventoryServiceImpl) RemoveQuantity(ctx context.Context, userID, itemID uint64, req ChangeInventoryQuantityRequest) (*domain.InventoryItem, error) {
+	return s.changeQuantity(ctx, userID, itemID, -int64(req.Quantity))
+}
+
+// --- AdjustInventory ---
+func (s *inventoryServiceImpl) AdjustInventory(ctx context.Context, userID uint64, req AdjustInventoryRequest) ([]domain.InventoryItem, error) {
+	deltas := make([]domain.InventoryDelta, 0, len(req.Adjustments))
+	itemIDs := make([]uint64, 0, len(req.Adjustments))
+	seen := make(map[uint64]bool, len(req.Adjustments))
+	for _, adj := range req.Adjustments {
+		deltas = append(deltas, domain.InventoryDelta{ItemID: adj.ItemID, Delta: adj.Delta})
+		if !seen[adj.ItemID] {
+			seen[adj.ItemID] = true
+			itemIDs = append(itemIDs, adj.ItemID)
+		}
+	}
+
+	if err := s.adjust(ctx, userID, deltas); err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.InventoryItem, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		entry, err := s.inventoryStore.GetInventoryItem(ctx, userID, itemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch inventory item %d after adjustment: %w", itemID, err)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// --- DeleteInventoryItem ---
+func (s *inventoryServiceImpl) DeleteInventoryItem(ctx context.Context, userID, itemID uint64) error {
+	err := s.inventoryStore.DeleteInventoryItem(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("cannot delete inventory item: %w", err)
+		}
+		return fmt.Errorf("failed to delete inventory item: %w", err)
+	}
+	return nil
+}
+
+// ListInventory retrieves a paginated list of the user's inventory using the storage layer
+// and constructs the PaginatedResponse.
+func (s *inventoryServiceImpl) ListInventory(
+	ctx context.Context,
+	params pagination.ListParams[domain.InventoryFilters],
+) (pagination.PaginatedResponse[domain.InventoryItem], error) {
+	entries, total, err := s.inventoryStore.ListInventory(ctx, params)
+	if err != nil {
+		return pagination.PaginatedResponse[domain.InventoryItem]{}, fmt.Errorf("failed to list inventory: %w", err)
+	}
+
+	return pagination.NewPaginatedResponse(entries, total, params.Page, params.PerPage), nil
+}
+
+// List (Generic Interface)
+func (s *inventoryServiceImpl) List(ctx context.Context, params pagination.ListParams[domain.InventoryFilters]) (pagination.PaginatedResponse[domain.InventoryItem], error) {
+	return s.ListInventory(ctx, params)
+}
+
+// ListByCursor (Generic Interface)
+func (s *inventoryServiceImpl) ListByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.InventoryFilters],
+) (pagination.CursorResponse[domain.InventoryItem], error) {
+	entries, page, err := s.inventoryStore.ListInventoryByCursor(ctx, params)
+	if err != nil {
+		return pagination.CursorResponse[domain.InventoryItem]{}, fmt.Errorf("failed to list inventory: %w", err)
+	}
+	return pagination.NewCursorResponse(entries, page, params.PerPage), nil
+}
+
+// ListState (Generic Interface)
+func (s *inventoryServiceImpl) ListState(
+	ctx context.Context,
+	params pagination.ListParams[domain.InventoryFilters],
+) (pagination.ListState, error) {
+	state, err := s.inventoryStore.GetInventoryListState(ctx, params.Filters)
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("failed to summarize inventory: %w", err)
+	}
+	return state, nil
+}
+
+// changeQuantity applies a single delta and returns the updated entry.
+func (s *inventoryServiceImpl) changeQuantity(ctx context.Context, userID, itemID uint64, delta int64) (*domain.InventoryItem, error) {
+	if err := s.adjust(ctx, userID, []domain.InventoryDelta{{ItemID: itemID, Delta: delta}}); err != nil {
+		return nil, err
+	}
+	return s.fetchAfterWrite(ctx, userID, itemID)
+}
+
+func (s *inventoryServiceImpl) adjust(ctx context.Context, userID uint64, deltas []domain.InventoryDelta) error {
+	err := s.inventoryStore.AdjustInventory(ctx, userID, deltas)
+	if err != nil {
+		if errors.Is(err, storage.ErrInsufficientQuantity) || errors.Is(err, storage.ErrInvalidReference) {
+			return fmt.Errorf("failed to adjust inventory: %w", err)
+		}
+		return fmt.Errorf("failed to store inventory adjustment: %w", err)
+	}
+	return nil
+}
+
+// fetchAfterWrite re-reads an entry after it was written. Unlike the other services there
+// is no fallback value to return, so a failed fetch is reported as an error.
+func (s *inventoryServiceImpl) fetchAfterWrite(ctx context.Context, userID, itemID uint64) (*domain.InventoryItem, error) {
+	entry, err := s.inventoryStore.GetInventoryItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch inventory item %d of user %d after saving: %w", itemID, userID, err)
+	}
+	return entry, nil
+}
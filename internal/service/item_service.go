@@ -9,25 +9,52 @@ import (
 
 // CreateItemRequest defines the payload for creating a new item.
 type CreateItemRequest struct {
-	Name          string                `json:"name" validate:"required,min=2,max=255"` // Required, length constraints
-	IsRawMaterial bool                  `json:"is_raw_material"`                        // No specific tag needed unless required=true
-	Description   domain.JSONNullString `json:"description"`                            // Validation on NullString needs custom validator or check Valid flag
-	ImageURL      domain.JSONNullString `json:"image_url" validate:"omitempty,url"`     // Optional, URL if present
+	Name          string                `json:"name" validate:"required,min=2,max=255"`     // Required, length constraints
+	IsRawMaterial bool                  `json:"is_raw_material"`                            // No specific tag needed unless required=true
+	Description   domain.JSONNullString `json:"description"`                                // Validation on NullString needs custom validator or check Valid flag
+	ImageURL      domain.JSONNullString `json:"image_url" validate:"omitempty,url,max=255"` // Optional, URL if present
 }
 
 // UpdateItemRequest defines the payload for updating an existing item.
 type UpdateItemRequest struct {
-	Name          *string               `json:"name" validate:"omitempty,min=2,max=255"` // Optional, but length constraints if present
-	IsRawMaterial *bool                 `json:"is_raw_material"`                         // Optional
-	Description   domain.JSONNullString `json:"description"`                             // Handled by NullString
-	ImageURL      domain.JSONNullString `json:"image_url" validate:"omitempty,url"`      // Optional, URL if present
+	Name          *string               `json:"name" validate:"omitempty,min=2,max=255"`    // Optional, but length constraints if present
+	IsRawMaterial *bool                 `json:"is_raw_material"`                            // Optional
+	Description   domain.JSONNullString `json:"description"`                                // Handled by NullString
+	ImageURL      domain.JSONNullString `json:"image_url" validate:"omitempty,url,max=255"` // Optional, URL if present
+}
+
+// PatchItemRequest is a JSON Merge Patch (RFC 7396) of an item: absent fields are left
+// unchanged, null clears a nullable field and a value replaces it.
+type PatchItemRequest struct {
+	Name          domain.Optional[string]                `json:"name" validate:"omitempty,min=2,max=255"`
+	IsRawMaterial domain.Optional[bool]                  `json:"is_raw_material"`
+	Description   domain.Optional[domain.JSONNullString] `json:"description"`
+	ImageURL      domain.Optional[domain.JSONNullString] `json:"image_url" validate:"omitempty,url,max=255"`
+}
+
+// PutItemRequest defines the full representation of an item stored under a slug.
+// Unlike the other requests the slug is chosen by the client and never regenerated.
+type PutItemRequest struct {
+	Name          string                `json:"name" validate:"required,min=2,max=255"`
+	IsRawMaterial bool                  `json:"is_raw_material"`
+	Description   domain.JSONNullString `json:"description"`
+	ImageURL      domain.JSONNullString `json:"image_url" validate:"omitempty,url,max=255"`
 }
 
 // ItemService defines the interface for item-related business logic.
 type ItemService interface {
 	CreateItem(ctx context.Context, req CreateItemRequest) (*domain.Item, error)
 	GetItemByID(ctx context.Context, id uint64) (*domain.Item, error)
-	UpdateItem(ctx context.Context, id uint64, req UpdateItemRequest) (*domain.Item, error)
-	DeleteItem(ctx context.Context, id uint64) error
+	GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error)
+
+	// The writes below take the versions listed in an If-Match header. When it is non-empty
+	// they fail with ErrPreconditionFailed unless the item currently has one of them.
+
+	// PutItemBySlug creates or replaces the item with the slug and reports whether it was created.
+	PutItemBySlug(ctx context.Context, slug string, req PutItemRequest, ifMatch []uint64) (*domain.Item, bool, error)
+	UpdateItem(ctx context.Context, id uint64, req UpdateItemRequest, ifMatch []uint64) (*domain.Item, error)
+	PatchItem(ctx context.Context, id uint64, req PatchItemRequest, ifMatch []uint64) (*domain.Item, error)
+	DeleteItem(ctx context.Context, id uint64, ifMatch []uint64) error
+
 	ListItems(ctx context.Context, params pagination.ListParams[domain.ItemFilters]) (pagination.PaginatedResponse[domain.Item], error)
 }
@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
+	"log/slog"
+	"slices"
 
 	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/app/slug"
 	"github.com/dubbie/calculator-api/internal/domain"
 	"github.com/dubbie/calculator-api/internal/storage"
 )
@@ -20,30 +21,71 @@ var _ ListService[domain.Item, domain.ItemFilters] = (*itemServiceImpl)(nil)
 
 type itemServiceImpl struct {
 	itemStore storage.ItemStore
+	logger    *slog.Logger
 	// Add other dependencies like a RecipeStore if needed later
 }
 
 // NewItemService creates a new ItemService implementation.
 // Dependencies (like ItemStore) are injected via the constructor.
-func NewItemService(itemStore storage.ItemStore) ItemService {
+func NewItemService(itemStore storage.ItemStore, logger *slog.Logger) ItemService {
 	return &itemServiceImpl{
 		itemStore: itemStore,
+		logger:    logger,
 	}
 }
 
-// --- Slug Generation Helper ---
-// Maybe put this in a separate file or package if it's used elsewhere
-var (
-	nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9]+`)
-	whitespaceRegex      = regexp.MustCompile(`\s+`)
-)
+// --- Slug Helpers ---
+
+// validateSlug checks a slug supplied by a client instead of generated from a name.
+func validateSlug(s string) error {
+	if !slug.Valid(s) {
+		return fmt.Errorf("%w: slug %q must be 1 to %d lowercase letters, digits and dashes", ErrValidation, s, slug.MaxLength)
+	}
+	return nil
+}
 
-func generateSlug(name string) string {
-	slug := strings.ToLower(name)
-	slug = whitespaceRegex.ReplaceAllString(slug, "-")
-	slug = nonAlphanumericRegex.ReplaceAllString(slug, "")
-	slug = strings.Trim(slug, "-")
-	return slug
+// uniqueSlug derives a slug from name that no other row uses yet, appending -2, -3, ...
+// on collision. current is the row's own slug when renaming; it is kept while it still
+// matches the name so that case or punctuation edits don't move the resource.
+// Two concurrent writes can still pick the same slug, in which case the loser gets
+// the storage layer's duplicate entry error.
+func uniqueSlug(
+	ctx context.Context,
+	name, fallback, current string,
+	listSlugs func(ctx context.Context, prefix string) ([]string, error),
+) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = fallback
+	}
+	if current != "" && slug.IsVariant(current, base) {
+		return current, nil
+	}
+
+	taken, err := listSlugs(ctx, slug.SearchPrefix(base))
+	if err != nil {
+		return "", fmt.Errorf("failed to check slug availability: %w", err)
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// --- Version Helpers ---
+
+// checkIfMatch enforces an If-Match precondition against the current version of a resource.
+func checkIfMatch(ifMatch []uint64, current uint64) error {
+	if len(ifMatch) > 0 && !slices.Contains(ifMatch, current) {
+		return fmt.Errorf("%w: the current version is %d", ErrPreconditionFailed, current)
+	}
+	return nil
+}
+
+// conflictError reports a concurrent write that got in first as a failed precondition when
+// the client sent If-Match, since the version it expected is gone either way.
+func conflictError(err error, ifMatch []uint64) error {
+	if len(ifMatch) > 0 && errors.Is(err, storage.ErrConflict) {
+		return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+	}
+	return err
 }
 
 // --- CreateItem ---
@@ -51,18 +93,21 @@ func (s *itemServiceImpl) CreateItem(
 	ctx context.Context,
 	req CreateItemRequest,
 ) (*domain.Item, error) {
-	slug := generateSlug(req.Name)
+	itemSlug, err := uniqueSlug(ctx, req.Name, "item", "", s.itemStore.ListSlugsWithPrefix)
+	if err != nil {
+		return nil, err
+	}
 
 	// Map request to domain model
 	newItem := &domain.Item{
 		Name:          req.Name,
-		Slug:          slug,
+		Slug:          itemSlug,
 		IsRawMaterial: req.IsRawMaterial,
 		Description:   req.Description,
 		ImageURL:      req.ImageURL,
 	}
 
-	err := s.itemStore.CreateItem(ctx, newItem)
+	err = s.itemStore.CreateItem(ctx, newItem)
 	if err != nil {
 		if errors.Is(err, storage.ErrDuplicateEntry) {
 			return nil, fmt.Errorf("failed to create item: %w", err)
@@ -81,7 +126,7 @@ func (s *itemServiceImpl) CreateItem(
 	createdItem, err := s.itemStore.GetItemByID(ctx, newItem.ID)
 	if err != nil {
 		// Log this inconsistency but maybe return the newItem with ID anyway? Or fail?
-		fmt.Printf("WARNING: Failed to fetch item %d immediately after creation: %v\n", newItem.ID, err)
+		s.logger.WarnContext(ctx, "Failed to fetch item immediately after creation", "item_id", newItem.ID, "error", err)
 		// Let's return what we have, the ID is the most critical part populated.
 		// The caller might make a separate GET request if they need fresh timestamps immediately.
 		return newItem, nil
@@ -91,12 +136,37 @@ func (s *itemServiceImpl) CreateItem(
 }
 
 // --- UpdateItem ---
+// UpdateItem changes the fields set in req. Unlike a patch, description and image_url are
+// always replaced, so leaving them out clears them.
 func (s *itemServiceImpl) UpdateItem(
 	ctx context.Context,
 	id uint64,
 	req UpdateItemRequest,
+	ifMatch []uint64,
 ) (*domain.Item, error) {
-	// TODO: Add validation for the request struct `req`
+	patch := PatchItemRequest{
+		Description: domain.Optional[domain.JSONNullString]{Value: req.Description, Set: true},
+		ImageURL:    domain.Optional[domain.JSONNullString]{Value: req.ImageURL, Set: true},
+	}
+	if req.Name != nil {
+		patch.Name = domain.Optional[string]{Value: *req.Name, Set: true}
+	}
+	if req.IsRawMaterial != nil {
+		patch.IsRawMaterial = domain.Optional[bool]{Value: *req.IsRawMaterial, Set: true}
+	}
+	return s.PatchItem(ctx, id, patch, ifMatch)
+}
+
+// --- PatchItem ---
+func (s *itemServiceImpl) PatchItem(
+	ctx context.Context,
+	id uint64,
+	req PatchItemRequest,
+	ifMatch []uint64,
+) (*domain.Item, error) {
+	if req.Name.Null || req.IsRawMaterial.Null {
+		return nil, fmt.Errorf("%w: name and is_raw_material cannot be null", ErrValidation)
+	}
 
 	// 1. Get the existing item
 	existingItem, err := s.itemStore.GetItemByID(ctx, id)
@@ -104,25 +174,33 @@ func (s *itemServiceImpl) UpdateItem(
 		// Handles ErrNotFound already
 		return nil, fmt.Errorf("cannot update item: %w", err)
 	}
+	if err := checkIfMatch(ifMatch, existingItem.Version); err != nil {
+		return nil, err
+	}
 
 	// 2. Merge changes from request into existing item
 	updated := false
-	if req.Name != nil && *req.Name != existingItem.Name {
-		existingItem.Name = *req.Name
-		existingItem.Slug = generateSlug(existingItem.Name) // Regenerate slug if name changes
+	if name := req.Name.Or(existingItem.Name); name != existingItem.Name {
+		// Regenerate slug if name changes
+		itemSlug, err := uniqueSlug(ctx, name, "item", existingItem.Slug, s.itemStore.ListSlugsWithPrefix)
+		if err != nil {
+			return nil, err
+		}
+		existingItem.Name = name
+		existingItem.Slug = itemSlug
 		updated = true
 	}
-	if req.IsRawMaterial != nil && *req.IsRawMaterial != existingItem.IsRawMaterial {
-		existingItem.IsRawMaterial = *req.IsRawMaterial
+	if isRawMaterial := req.IsRawMaterial.Or(existingItem.IsRawMaterial); isRawMaterial != existingItem.IsRawMaterial {
+		existingItem.IsRawMaterial = isRawMaterial
 		updated = true
 	}
 	// For sql.NullString, check if the request field itself is different *or* its validity changes
-	if req.Description != existingItem.Description {
-		existingItem.Description = req.Description
+	if description := req.Description.Or(existingItem.Description); description != existingItem.Description {
+		existingItem.Description = description
 		updated = true
 	}
-	if req.ImageURL != existingItem.ImageURL {
-		existingItem.ImageURL = req.ImageURL
+	if imageURL := req.ImageURL.Or(existingItem.ImageURL); imageURL != existingItem.ImageURL {
+		existingItem.ImageURL = imageURL
 		updated = true
 	}
 
@@ -136,6 +214,7 @@ func (s *itemServiceImpl) UpdateItem(
 	// 3. Store the updated item
 	err = s.itemStore.UpdateItem(ctx, existingItem)
 	if err != nil {
+		err = conflictError(err, ifMatch)
 		if errors.Is(err, storage.ErrDuplicateEntry) {
 			// Possible if slug/name changed to an existing one
 			return nil, fmt.Errorf("failed to update item: %w", err)
@@ -151,7 +230,7 @@ func (s *itemServiceImpl) UpdateItem(
 	// Let's fetch again for consistency, like in Create.
 	updatedItem, fetchErr := s.itemStore.GetItemByID(ctx, id)
 	if fetchErr != nil {
-		fmt.Printf("WARNING: Failed to fetch item %d immediately after update: %v\n", id, fetchErr)
+		s.logger.WarnContext(ctx, "Failed to fetch item immediately after update", "item_id", id, "error", fetchErr)
 		// Return the item as it was before the failed fetch
 		return existingItem, nil
 	}
@@ -160,9 +239,23 @@ func (s *itemServiceImpl) UpdateItem(
 }
 
 // --- DeleteItem ---
-func (s *itemServiceImpl) DeleteItem(ctx context.Context, id uint64) error {
-	err := s.itemStore.DeleteItem(ctx, id)
+func (s *itemServiceImpl) DeleteItem(ctx context.Context, id uint64, ifMatch []uint64) error {
+	// With If-Match only the checked version may be deleted
+	var version *uint64
+	if len(ifMatch) > 0 {
+		existingItem, err := s.itemStore.GetItemByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cannot delete item: %w", err)
+		}
+		if err := checkIfMatch(ifMatch, existingItem.Version); err != nil {
+			return err
+		}
+		version = &existingItem.Version
+	}
+
+	err := s.itemStore.DeleteItem(ctx, id, version)
 	if err != nil {
+		err = conflictError(err, ifMatch)
 		if errors.Is(err, storage.ErrNotFound) {
 			return fmt.Errorf("cannot delete item: %w", err) // Wrap ErrNotFound
 		}
@@ -189,6 +282,94 @@ func (s *itemServiceImpl) GetItemByID(
 	return item, nil
 }
 
+// GetItemBySlug retrieves an item by its slug using the storage layer.
+func (s *itemServiceImpl) GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error) {
+	item, err := s.itemStore.GetItemBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("item with slug %q not found: %w", slug, err)
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	return item, nil
+}
+
+// PutItemBySlug creates the item under the given slug, or replaces the fields of the
+// item already using it. Repeating the same request leaves the item unchanged.
+func (s *itemServiceImpl) PutItemBySlug(
+	ctx context.Context,
+	slug string,
+	req PutItemRequest,
+	ifMatch []uint64,
+) (*domain.Item, bool, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, false, err
+	}
+
+	existingItem, err := s.itemStore.GetItemBySlug(ctx, slug)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	if existingItem == nil {
+		// If-Match only matches an existing item, so it can't be used to create one
+		if len(ifMatch) > 0 {
+			return nil, false, fmt.Errorf("%w: item %q does not exist", ErrPreconditionFailed, slug)
+		}
+
+		newItem := &domain.Item{
+			Name:          req.Name,
+			Slug:          slug,
+			IsRawMaterial: req.IsRawMaterial,
+			Description:   req.Description,
+			ImageURL:      req.ImageURL,
+		}
+		if err := s.itemStore.CreateItem(ctx, newItem); err != nil {
+			if errors.Is(err, storage.ErrDuplicateEntry) {
+				return nil, false, fmt.Errorf("failed to create item: %w", err)
+			}
+			return nil, false, fmt.Errorf("failed to store new item: %w", err)
+		}
+
+		createdItem, err := s.itemStore.GetItemByID(ctx, newItem.ID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to fetch item immediately after creation", "item_id", newItem.ID, "error", err)
+			return newItem, true, nil
+		}
+		return createdItem, true, nil
+	}
+
+	if err := checkIfMatch(ifMatch, existingItem.Version); err != nil {
+		return nil, false, err
+	}
+
+	replacement := *existingItem
+	replacement.Name = req.Name
+	replacement.IsRawMaterial = req.IsRawMaterial
+	replacement.Description = req.Description
+	replacement.ImageURL = req.ImageURL
+
+	// Repeating a PUT must not bump the version, which would change the ETag for nothing
+	if replacement == *existingItem {
+		return existingItem, false, nil
+	}
+
+	if err := s.itemStore.UpdateItem(ctx, &replacement); err != nil {
+		err = conflictError(err, ifMatch)
+		if errors.Is(err, storage.ErrDuplicateEntry) {
+			return nil, false, fmt.Errorf("failed to update item: %w", err)
+		}
+		return nil, false, fmt.Errorf("failed to store updated item: %w", err)
+	}
+
+	updatedItem, err := s.itemStore.GetItemByID(ctx, replacement.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch item immediately after update", "item_id", replacement.ID, "error", err)
+		return &replacement, false, nil
+	}
+	return updatedItem, false, nil
+}
+
 // ListItems retrieves a paginated list of items using the storage layer
 // and constructs the PaginatedResponse.
 func (s *itemServiceImpl) ListItems(
@@ -215,3 +396,27 @@ func (s *itemServiceImpl) List(ctx context.Context, params pagination.ListParams
 	// Keep existing implementation
 	return s.ListItems(ctx, params)
 }
+
+// ListByCursor (Generic Interface)
+func (s *itemServiceImpl) ListByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.ItemFilters],
+) (pagination.CursorResponse[domain.Item], error) {
+	items, page, err := s.itemStore.ListItemsByCursor(ctx, params)
+	if err != nil {
+		return pagination.CursorResponse[domain.Item]{}, fmt.Errorf("failed to list items: %w", err)
+	}
+	return pagination.NewCursorResponse(items, page, params.PerPage), nil
+}
+
+// ListState (Generic Interface)
+func (s *itemServiceImpl) ListState(
+	ctx context.Context,
+	params pagination.ListParams[domain.ItemFilters],
+) (pagination.ListState, error) {
+	state, err := s.itemStore.GetItemListState(ctx, params.Filters)
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("failed to summarize items: %w", err)
+	}
+	return state, nil
+}
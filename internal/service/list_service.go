@@ -10,6 +10,10 @@ import (
 // ListService defines a generic interface for listing resources.
 type ListService[T any, F any] interface {
 	List(ctx context.Context, params pagination.ListParams[F]) (pagination.PaginatedResponse[T], error)
+	// ListByCursor lists one page in cursor mode, without counting the total.
+	ListByCursor(ctx context.Context, params pagination.ListParams[F]) (pagination.CursorResponse[T], error)
+	// ListState summarizes the rows matched by the filters, for validating cached lists.
+	ListState(ctx context.Context, params pagination.ListParams[F]) (pagination.ListState, error)
 }
 
 // FilterParser defines a function type for parsing specific filter structs from query params.
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// SetPreferredRecipeRequest defines the payload for choosing a user's recipe for an item.
+type SetPreferredRecipeRequest struct {
+	RecipeID uint64 `json:"recipe_id" validate:"required"`
+}
+
+// PreferredRecipeService defines the interface for managing per-user recipe preferences
+// and resolving which recipe is used for an item.
+type PreferredRecipeService interface {
+	SetPreferredRecipe(ctx context.Context, userID, itemID uint64, req SetPreferredRecipeRequest) (*domain.UserPreferredRecipe, error)
+	ClearPreferredRecipe(ctx context.Context, userID, itemID uint64) error
+	ListPreferredRecipes(ctx context.Context, userID uint64) ([]domain.UserPreferredRecipe, error)
+	// ResolveRecipe picks the recipe for an item, honoring the user's preference when userID is set.
+	ResolveRecipe(ctx context.Context, itemID uint64, userID *uint64) (*domain.ResolvedRecipe, error)
+}
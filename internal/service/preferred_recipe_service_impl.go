@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure preferredRecipeServiceImpl implements PreferredRecipeService
+var _ PreferredRecipeService = (*preferredRecipeServiceImpl)(nil)
+
+type preferredRecipeServiceImpl struct {
+	itemStore            storage.ItemStore
+	recipeStore          storage.RecipeStore
+	preferredRecipeStore storage.PreferredRecipeStore
+	resolver             *recipeResolver
+	logger               *slog.Logger
+}
+
+// NewPreferredRecipeService creates a new PreferredRecipeService implementation.
+func NewPreferredRecipeService(
+	itemStore storage.ItemStore,
+	recipeStore storage.RecipeStore,
+	preferredRecipeStore storage.PreferredRecipeStore,
+	logger *slog.Logger,
+) PreferredRecipeService {
+	return &preferredRecipeServiceImpl{
+		itemStore:            itemStore,
+		recipeStore:          recipeStore,
+		preferredRecipeStore: preferredRecipeStore,
+		resolver: &recipeResolver{
+			recipeStore:          recipeStore,
+			preferredRecipeStore: preferredRecipeStore,
+		},
+		logger: logger,
+	}
+}
+
+// SetPreferredRecipe stores the user's preferred recipe for an item after checking
+// that the recipe actually outputs that item.
+func (s *preferredRecipeServiceImpl) SetPreferredRecipe(
+	ctx context.Context,
+	userID, itemID uint64,
+	req SetPreferredRecipeRequest,
+) (*domain.UserPreferredRecipe, error) {
+	recipe, err := s.recipeStore.GetRecipeByID(ctx, req.RecipeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: recipe %d does not exist", storage.ErrInvalidReference, req.RecipeID)
+		}
+		return nil, fmt.Errorf("failed to load recipe %d: %w", req.RecipeID, err)
+	}
+
+	outputsItem := false
+	for _, output := range recipe.Outputs {
+		if output.ItemID == itemID {
+			outputsItem = true
+			break
+		}
+	}
+	if !outputsItem {
+		return nil, fmt.Errorf("%w: recipe %d does not output item %d", ErrValidation, req.RecipeID, itemID)
+	}
+
+	pref := &domain.UserPreferredRecipe{
+		UserID:            userID,
+		OutputItemID:      itemID,
+		PreferredRecipeID: req.RecipeID,
+	}
+	if err := s.preferredRecipeStore.SetPreferredRecipe(ctx, pref); err != nil {
+		if errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to set preferred recipe: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store preferred recipe: %w", err)
+	}
+
+	storedPref, err := s.preferredRecipeStore.GetPreferredRecipe(ctx, userID, itemID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch preferred recipe immediately after saving",
+			"user_id", userID, "item_id", itemID, "error", err)
+		return pref, nil
+	}
+
+	return storedPref, nil
+}
+
+// ClearPreferredRecipe removes the user's preference for an item.
+func (s *preferredRecipeServiceImpl) ClearPreferredRecipe(ctx context.Context, userID, itemID uint64) error {
+	err := s.preferredRecipeStore.DeletePreferredRecipe(ctx, userID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("cannot clear preferred recipe: %w", err)
+		}
+		return fmt.Errorf("failed to clear preferred recipe: %w", err)
+	}
+	return nil
+}
+
+// ListPreferredRecipes retrieves all of a user's preferences.
+func (s *preferredRecipeServiceImpl) ListPreferredRecipes(ctx context.Context, userID uint64) ([]domain.UserPreferredRecipe, error) {
+	prefs, err := s.preferredRecipeStore.ListPreferredRecipes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preferred recipes: %w", err)
+	}
+	return prefs, nil
+}
+
+// ResolveRecipe picks the recipe for an item and reports which rule chose it.
+func (s *preferredRecipeServiceImpl) ResolveRecipe(ctx context.Context, itemID uint64, userID *uint64) (*domain.ResolvedRecipe, error) {
+	if _, err := s.itemStore.GetItemByID(ctx, itemID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("item with id %d not found: %w", itemID, err)
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	resolved, err := s.resolver.resolve(ctx, itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.Recipe == nil {
+		return nil, fmt.Errorf("cannot resolve recipe for item %d: %w", itemID, ErrNoRecipe)
+	}
+
+	return resolved, nil
+}
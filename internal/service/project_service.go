@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ProjectItemRequest describes a target item of a project.
+type ProjectItemRequest struct {
+	ItemID           uint64 `json:"item_id" validate:"required"`
+	TargetQuantity   uint64 `json:"target_quantity" validate:"required,min=1,max=1000000000"`
+	ProducedQuantity uint64 `json:"produced_quantity" validate:"max=1000000000"`
+}
+
+// CreateProjectRequest defines the payload for creating a new project.
+type CreateProjectRequest struct {
+	Name        string                `json:"name" validate:"required,min=1,max=255"`
+	Description domain.JSONNullString `json:"description"`
+	Items       []ProjectItemRequest  `json:"items" validate:"max=500,dive"`
+}
+
+// UpdateProjectRequest defines the payload for updating a project's name and description.
+// Omitted fields keep their current value; a null description clears it.
+type UpdateProjectRequest struct {
+	Name        *string                                `json:"name" validate:"omitempty,min=1,max=255"`
+	Description domain.Optional[domain.JSONNullString] `json:"description"`
+}
+
+// SetProjectItemRequest defines the payload for adding an item to a project or changing its target.
+// ProducedQuantity keeps the recorded progress when omitted.
+type SetProjectItemRequest struct {
+	TargetQuantity   uint64  `json:"target_quantity" validate:"required,min=1,max=1000000000"`
+	ProducedQuantity *uint64 `json:"produced_quantity" validate:"omitempty,max=1000000000"`
+}
+
+// UpdateProgressRequest defines the payload for recording how much of a project item has been produced.
+type UpdateProgressRequest struct {
+	ProducedQuantity *uint64 `json:"produced_quantity" validate:"required,max=1000000000"`
+}
+
+// ProjectService defines the interface for managing users' projects.
+// Every method is scoped to the owning user; other users' projects are reported as not found.
+type ProjectService interface {
+	CreateProject(ctx context.Context, userID uint64, req CreateProjectRequest) (*domain.Project, error)
+	GetProjectByID(ctx context.Context, userID, id uint64) (*domain.Project, error)
+	UpdateProject(ctx context.Context, userID, id uint64, req UpdateProjectRequest) (*domain.Project, error)
+	DeleteProject(ctx context.Context, userID, id uint64) error
+	ListProjects(ctx context.Context, params pagination.ListParams[domain.ProjectFilters]) (pagination.PaginatedResponse[domain.Project], error)
+
+	SetProjectItem(ctx context.Context, userID, projectID, itemID uint64, req SetProjectItemRequest) (*domain.Project, error)
+	UpdateProgress(ctx context.Context, userID, projectID, itemID uint64, req UpdateProgressRequest) (*domain.Project, error)
+	RemoveProjectItem(ctx context.Context, userID, projectID, itemID uint64) error
+
+	// GetRequirements expands the project's remaining quantities into raw materials.
+	GetRequirements(ctx context.Context, userID, id uint64) (*domain.ProjectRequirements, error)
+}
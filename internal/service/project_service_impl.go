@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// Ensure projectServiceImpl implements ProjectService
+var _ ProjectService = (*projectServiceImpl)(nil)
+
+// Ensure projectServiceImpl implements the generic ListService for projects
+var _ ListService[domain.Project, domain.ProjectFilters] = (*projectServiceImpl)(nil)
+
+type projectServiceImpl struct {
+	projectStore storage.ProjectStore
+	itemStore    storage.ItemStore
+	resolver     *recipeResolver
+	logger       *slog.Logger
+}
+
+// NewProjectService creates a new ProjectService implementation.
+func NewProjectService(
+	projectStore storage.ProjectStore,
+	itemStore storage.ItemStore,
+	recipeStore storage.RecipeStore,
+	preferredRecipeStore storage.PreferredRecipeStore,
+	logger *slog.Logger,
+) ProjectService {
+	return &projectServiceImpl{
+		projectStore: projectStore,
+		itemStore:    itemStore,
+		resolver: &recipeResolver{
+			recipeStore:          recipeStore,
+			preferredRecipeStore: preferredRecipeStore,
+		},
+		logger: logger,
+	}
+}
+
+// --- CreateProject ---
+func (s *projectServiceImpl) CreateProject(ctx context.Context, userID uint64, req CreateProjectRequest) (*domain.Project, error) {
+	items := make([]domain.ProjectItem, 0, len(req.Items))
+	seen := make(map[uint64]bool, len(req.Items))
+	for _, itemReq := range req.Items {
+		if seen[itemReq.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is listed more than once", ErrValidation, itemReq.ItemID)
+		}
+		seen[itemReq.ItemID] = true
+
+		items = append(items, domain.ProjectItem{
+			ItemID:           itemReq.ItemID,
+			TargetQuantity:   itemReq.TargetQuantity,
+			ProducedQuantity: itemReq.ProducedQuantity,
+		})
+	}
+
+	newProject := &domain.Project{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Items:       items,
+	}
+
+	err := s.projectStore.CreateProject(ctx, newProject)
+	if err != nil {
+		if errors.Is(err, storage.ErrDuplicateEntry) || errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to create project: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store new project: %w", err)
+	}
+
+	if newProject.ID == 0 {
+		return nil, errors.New("failed to retrieve ID after project creation")
+	}
+
+	createdProject, err := s.projectStore.GetProjectByID(ctx, newProject.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch project immediately after creation", "project_id", newProject.ID, "error", err)
+		return newProject, nil
+	}
+
+	return createdProject, nil
+}
+
+// GetProjectByID retrieves one of the user's projects with its items.
+func (s *projectServiceImpl) GetProjectByID(ctx context.Context, userID, id uint64) (*domain.Project, error) {
+	project, err := s.projectStore.GetProjectByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("project with id %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	// Don't reveal that other users' projects exist
+	if project.UserID != userID {
+		return nil, fmt.Errorf("project with id %d not found: %w", id, storage.ErrNotFound)
+	}
+	return project, nil
+}
+
+// --- UpdateProject ---
+func (s *projectServiceImpl) UpdateProject(ctx context.Context, userID, id uint64, req UpdateProjectRequest) (*domain.Project, error) {
+	// 1. Get the existing project
+	existingProject, err := s.GetProjectByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update project: %w", err)
+	}
+
+	// 2. Merge changes from request into existing project
+	if req.Name != nil {
+		existingProject.Name = *req.Name
+	}
+	existingProject.Description = req.Description.Or(existingProject.Description)
+
+	// 3. Store the updated project
+	err = s.projectStore.UpdateProject(ctx, existingProject)
+	if err != nil {
+		if errors.Is(err, storage.ErrDuplicateEntry) {
+			return nil, fmt.Errorf("failed to update project: %w", err)
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("failed to update project, inconsistency detected: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store updated project: %w", err)
+	}
+
+	return s.fetchAfterWrite(ctx, existingProject, "update")
+}
+
+// --- DeleteProject ---
+func (s *projectServiceImpl) DeleteProject(ctx context.Context, userID, id uint64) error {
+	if _, err := s.GetProjectByID(ctx, userID, id); err != nil {
+		return fmt.Errorf("cannot delete project: %w", err)
+	}
+
+	err := s.projectStore.DeleteProject(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("cannot delete project: %w", err)
+		}
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return nil
+}
+
+// ListProjects retrieves a paginated list of projects using the storage layer
+// and constructs the PaginatedResponse.
+func (s *projectServiceImpl) ListProjects(
+	ctx context.Context,
+	params pagination.ListParams[domain.ProjectFilters],
+) (pagination.PaginatedResponse[domain.Project], error) {
+	projects, total, err := s.projectStore.ListProjects(ctx, params)
+	if err != nil {
+		return pagination.PaginatedResponse[domain.Project]{}, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	return pagination.NewPaginatedResponse(projects, total, params.Page, params.PerPage), nil
+}
+
+// List (Generic Interface)
+func (s *projectServiceImpl) List(ctx context.Context, params pagination.ListParams[domain.ProjectFilters]) (pagination.PaginatedResponse[domain.Project], error) {
+	return s.ListProjects(ctx, params)
+}
+
+// ListByCursor (Generic Interface)
+func (s *projectServiceImpl) ListByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.ProjectFilters],
+) (pagination.CursorResponse[domain.Project], error) {
+	projects, page, err := s.projectStore.ListProjectsByCursor(ctx, params)
+	if err != nil {
+		return pagination.CursorResponse[domain.Project]{}, fmt.Errorf("failed to list projects: %w", err)
+	}
+	return pagination.NewCursorResponse(projects, page, params.PerPage), nil
+}
+
+// ListState (Generic Interface)
+func (s *projectServiceImpl) ListState(
+	ctx context.Context,
+	params pagination.ListParams[domain.ProjectFilters],
+) (pagination.ListState, error) {
+	state, err := s.projectStore.GetProjectListState(ctx, params.Filters)
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("failed to summarize projects: %w", err)
+	}
+	return state, nil
+}
+
+// --- SetProjectItem ---
+func (s *projectServiceImpl) SetProjectItem(ctx context.Context, userID, projectID, itemID uint64, req SetProjectItemRequest) (*domain.Project, error) {
+	project, err := s.GetProjectByID(ctx, userID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set project item: %w", err)
+	}
+
+	item := domain.ProjectItem{
+		ProjectID:      projectID,
+		ItemID:         itemID,
+		TargetQuantity: req.TargetQuantity,
+	}
+	if req.ProducedQuantity != nil {
+		item.ProducedQuantity = *req.ProducedQuantity
+	} else if existing := findProjectItem(project, itemID); existing != nil {
+		item.ProducedQuantity = existing.ProducedQuantity
+	}
+
+	return s.storeProjectItem(ctx, project, &item)
+}
+
+// --- UpdateProgress ---
+func (s *projectServiceImpl) UpdateProgress(ctx context.Context, userID, projectID, itemID uint64, req UpdateProgressRequest) (*domain.Project, error) {
+	project, err := s.GetProjectByID(ctx, userID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update progress: %w", err)
+	}
+
+	existing := findProjectItem(project, itemID)
+	if existing == nil {
+		return nil, fmt.Errorf("item %d is not part of project %d: %w", itemID, projectID, storage.ErrNotFound)
+	}
+
+	item := *existing
+	item.ProducedQuantity = *req.ProducedQuantity
+
+	return s.storeProjectItem(ctx, project, &item)
+}
+
+// --- RemoveProjectItem ---
+func (s *projectServiceImpl) RemoveProjectItem(ctx context.Context, userID, projectID, itemID uint64) error {
+	if _, err := s.GetProjectByID(ctx, userID, projectID); err != nil {
+		return fmt.Errorf("cannot remove project item: %w", err)
+	}
+
+	err := s.projectStore.DeleteProjectItem(ctx, projectID, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("cannot remove project item: %w", err)
+		}
+		return fmt.Errorf("failed to remove project item: %w", err)
+	}
+	return nil
+}
+
+// GetRequirements expands every target's remaining quantity in one crafting tree, so
+// shared intermediates and surplus are pooled across the whole project. Recipes are
+// picked with the owner's preferences.
+func (s *projectServiceImpl) GetRequirements(ctx context.Context, userID, id uint64) (*domain.ProjectRequirements, error) {
+	project, err := s.GetProjectByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := newCraftingTree(s.itemStore, s.resolver, &userID)
+	remaining := make(map[uint64]uint64, len(project.Items))
+	for _, item := range project.Items {
+		if item.RemainingQuantity == 0 {
+			continue
+		}
+		remaining[item.ItemID] = item.RemainingQuantity
+		if err := tree.require(ctx, item.ItemID, item.RemainingQuantity); err != nil {
+			return nil, fmt.Errorf("failed to calculate requirements of project %d: %w", id, err)
+		}
+	}
+	if err := tree.loadPoolItems(ctx); err != nil {
+		return nil, fmt.Errorf("failed to calculate requirements of project %d: %w", id, err)
+	}
+
+	// Targets aren't intermediates, unless another target also consumes them
+	for itemID, quantity := range remaining {
+		tree.crafted[itemID] -= min(tree.crafted[itemID], quantity)
+	}
+
+	return &domain.ProjectRequirements{
+		ProjectID:      project.ID,
+		Remaining:      tree.itemQuantities(remaining),
+		RawMaterials:   tree.itemQuantities(tree.raw),
+		Intermediates:  tree.itemQuantities(tree.crafted),
+		Crafts:         tree.recipeRuns(),
+		Leftovers:      tree.itemQuantities(tree.pool),
+		MissingRecipes: tree.itemQuantities(tree.missing),
+	}, nil
+}
+
+// storeProjectItem upserts the item and returns the project as stored afterwards.
+func (s *projectServiceImpl) storeProjectItem(ctx context.Context, project *domain.Project, item *domain.ProjectItem) (*domain.Project, error) {
+	err := s.projectStore.SetProjectItem(ctx, item)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to set project item: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store project item: %w", err)
+	}
+
+	return s.fetchAfterWrite(ctx, project, "item update")
+}
+
+// fetchAfterWrite re-reads a project after a write, falling back to the given copy.
+func (s *projectServiceImpl) fetchAfterWrite(ctx context.Context, project *domain.Project, operation string) (*domain.Project, error) {
+	storedProject, err := s.projectStore.GetProjectByID(ctx, project.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch project immediately after write",
+			"project_id", project.ID, "operation", operation, "error", err)
+		return project, nil
+	}
+	return storedProject, nil
+}
+
+// findProjectItem returns the project's entry for the item, or nil if it has none.
+func findProjectItem(project *domain.Project, itemID uint64) *domain.ProjectItem {
+	for i := range project.Items {
+		if project.Items[i].ItemID == itemID {
+			return &project.Items[i]
+		}
+	}
+	return nil
+}
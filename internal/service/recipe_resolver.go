@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// recipeResolver picks the recipe used to craft an item: the user's preferred recipe
+// first, then a recipe flagged is_default, then a deterministic fallback.
+type recipeResolver struct {
+	recipeStore          storage.RecipeStore
+	preferredRecipeStore storage.PreferredRecipeStore
+}
+
+// resolve returns the chosen recipe for the item. The result's Recipe is nil when no
+// recipe produces the item. userID is optional; without it preferences are skipped.
+func (r *recipeResolver) resolve(ctx context.Context, itemID uint64, userID *uint64) (*domain.ResolvedRecipe, error) {
+	resolved := &domain.ResolvedRecipe{ItemID: itemID, UserID: userID}
+
+	// Ordered is_default first, then primary output, then ID
+	candidates, err := r.recipeStore.ListRecipesByOutputItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipes for item %d: %w", itemID, err)
+	}
+	if len(candidates) == 0 {
+		return resolved, nil
+	}
+
+	if userID != nil {
+		pref, err := r.preferredRecipeStore.GetPreferredRecipe(ctx, *userID, itemID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load preferred recipe for item %d: %w", itemID, err)
+		}
+		// A preference only counts while the recipe still produces the item
+		if pref != nil {
+			for i := range candidates {
+				if candidates[i].ID == pref.PreferredRecipeID {
+					resolved.Rule = domain.RecipeRulePreferred
+					resolved.Recipe = &candidates[i]
+					return resolved, nil
+				}
+			}
+		}
+	}
+
+	resolved.Recipe = &candidates[0]
+	if candidates[0].IsDefault {
+		resolved.Rule = domain.RecipeRuleDefault
+	} else {
+		resolved.Rule = domain.RecipeRuleFallback
+	}
+	return resolved, nil
+}
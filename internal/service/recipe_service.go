@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// RecipeInputRequest describes an item consumed by a recipe.
+type RecipeInputRequest struct {
+	ItemID   uint64 `json:"item_id" validate:"required"`
+	Quantity uint32 `json:"quantity" validate:"required,min=1"`
+}
+
+// RecipeOutputRequest describes an item produced by a recipe.
+type RecipeOutputRequest struct {
+	ItemID          uint64  `json:"item_id" validate:"required"`
+	Quantity        uint32  `json:"quantity" validate:"omitempty,min=1"`         // Defaults to 1
+	Chance          *uint32 `json:"chance" validate:"omitempty,min=1,max=10000"` // Basis points, defaults to 10000 (100%)
+	IsPrimaryOutput bool    `json:"is_primary_output"`
+}
+
+// CreateRecipeRequest defines the payload for creating a new recipe.
+type CreateRecipeRequest struct {
+	Name             domain.JSONNullString `json:"name"`
+	CraftingMethodID uint64                `json:"crafting_method_id" validate:"required"`
+	EUPerTick        domain.JSONNullInt64  `json:"eu_per_tick"`
+	DurationTicks    domain.JSONNullInt64  `json:"duration_ticks"`
+	Notes            domain.JSONNullString `json:"notes"`
+	IsDefault        bool                  `json:"is_default"`
+	Inputs           []RecipeInputRequest  `json:"inputs" validate:"dive"`
+	Outputs          []RecipeOutputRequest `json:"outputs" validate:"required,min=1,dive"`
+}
+
+// UpdateRecipeRequest defines the payload for updating an existing recipe.
+// Only the fields present in the payload are changed; null clears a nullable field.
+// Inputs and Outputs replace the stored ones entirely when provided.
+type UpdateRecipeRequest struct {
+	Name             domain.Optional[domain.JSONNullString] `json:"name"`
+	CraftingMethodID *uint64                                `json:"crafting_method_id" validate:"omitempty,min=1"`
+	EUPerTick        domain.Optional[domain.JSONNullInt64]  `json:"eu_per_tick"`
+	DurationTicks    domain.Optional[domain.JSONNullInt64]  `json:"duration_ticks"`
+	Notes            domain.Optional[domain.JSONNullString] `json:"notes"`
+	IsDefault        *bool                                  `json:"is_default"`
+	Inputs           *[]RecipeInputRequest                  `json:"inputs" validate:"omitempty,dive"`
+	Outputs          *[]RecipeOutputRequest                 `json:"outputs" validate:"omitempty,min=1,dive"`
+}
+
+// YieldRequest defines the query parameters for a recipe yield report.
+// TargetItemID and TargetQuantity go together and ask how many runs collect that much of an output.
+type YieldRequest struct {
+	Runs           uint64   `schema:"runs" json:"runs" validate:"required,min=1,max=1000000"`
+	Confidence     *float64 `schema:"confidence" json:"confidence" validate:"omitempty,gt=0,lt=1"` // Defaults to 0.95
+	TargetItemID   *uint64  `schema:"target_item_id" json:"target_item_id" validate:"required_with=TargetQuantity,omitempty,min=1"`
+	TargetQuantity *uint64  `schema:"target_quantity" json:"target_quantity" validate:"required_with=TargetItemID,omitempty,min=1,max=1000000"`
+}
+
+// RecipeService defines the interface for recipe-related business logic.
+type RecipeService interface {
+	CreateRecipe(ctx context.Context, req CreateRecipeRequest) (*domain.Recipe, error)
+	GetRecipeByID(ctx context.Context, id uint64) (*domain.Recipe, error)
+	UpdateRecipe(ctx context.Context, id uint64, req UpdateRecipeRequest) (*domain.Recipe, error)
+	DeleteRecipe(ctx context.Context, id uint64) error
+	ListRecipes(ctx context.Context, params pagination.ListParams[domain.RecipeFilters]) (pagination.PaginatedResponse[domain.Recipe], error)
+	ListCycles(ctx context.Context) (*domain.RecipeCycleReport, error)
+	CalculateYield(ctx context.Context, id uint64, req YieldRequest) (*domain.RecipeYield, error)
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/dubbie/calculator-api/internal/app/binomial"
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+const (
+	// maxReportedCycles caps how many cycles ListCycles enumerates; dense graphs can have exponentially many.
+	maxReportedCycles = 1000
+	// defaultYieldConfidence is used when a yield request doesn't specify one.
+	defaultYieldConfidence = 0.95
+)
+
+// Ensure recipeServiceImpl implements RecipeService
+var _ RecipeService = (*recipeServiceImpl)(nil)
+
+// Ensure recipeServiceImpl implements the generic ListService for recipes
+var _ ListService[domain.Recipe, domain.RecipeFilters] = (*recipeServiceImpl)(nil)
+
+type recipeServiceImpl struct {
+	recipeStore  storage.RecipeStore
+	cycleChecker *recipegraph.Checker
+	logger       *slog.Logger
+}
+
+// NewRecipeService creates a new RecipeService implementation.
+// Recipes that would close a dependency loop are rejected using the cycle checker.
+func NewRecipeService(recipeStore storage.RecipeStore, cycleChecker *recipegraph.Checker, logger *slog.Logger) RecipeService {
+	return &recipeServiceImpl{
+		recipeStore:  recipeStore,
+		cycleChecker: cycleChecker,
+		logger:       logger,
+	}
+}
+
+// --- CreateRecipe ---
+func (s *recipeServiceImpl) CreateRecipe(ctx context.Context, req CreateRecipeRequest) (*domain.Recipe, error) {
+	inputs, err := buildRecipeInputs(req.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := buildRecipeOutputs(req.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRecipeEnergy(req.EUPerTick, req.DurationTicks); err != nil {
+		return nil, err
+	}
+
+	newRecipe := &domain.Recipe{
+		Name:             req.Name,
+		CraftingMethodID: req.CraftingMethodID,
+		EUPerTick:        req.EUPerTick,
+		DurationTicks:    req.DurationTicks,
+		Notes:            req.Notes,
+		IsDefault:        req.IsDefault,
+		Inputs:           inputs,
+		Outputs:          outputs,
+	}
+
+	if err := s.cycleChecker.CheckRecipe(ctx, newRecipe); err != nil {
+		return nil, fmt.Errorf("cannot create recipe: %w", err)
+	}
+
+	err = s.recipeStore.CreateRecipe(ctx, newRecipe)
+	if err != nil {
+		if errors.Is(err, storage.ErrDuplicateEntry) || errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to create recipe: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store new recipe: %w", err)
+	}
+
+	if newRecipe.ID == 0 {
+		return nil, errors.New("failed to retrieve ID after recipe creation")
+	}
+
+	createdRecipe, err := s.recipeStore.GetRecipeByID(ctx, newRecipe.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch recipe immediately after creation", "recipe_id", newRecipe.ID, "error", err)
+		return newRecipe, nil
+	}
+
+	return createdRecipe, nil
+}
+
+// --- UpdateRecipe ---
+func (s *recipeServiceImpl) UpdateRecipe(ctx context.Context, id uint64, req UpdateRecipeRequest) (*domain.Recipe, error) {
+	// 1. Get the existing recipe
+	existingRecipe, err := s.recipeStore.GetRecipeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update recipe: %w", err)
+	}
+
+	// 2. Merge changes from request into existing recipe
+	existingRecipe.Name = req.Name.Or(existingRecipe.Name)
+	existingRecipe.EUPerTick = req.EUPerTick.Or(existingRecipe.EUPerTick)
+	existingRecipe.DurationTicks = req.DurationTicks.Or(existingRecipe.DurationTicks)
+	existingRecipe.Notes = req.Notes.Or(existingRecipe.Notes)
+	if req.CraftingMethodID != nil {
+		existingRecipe.CraftingMethodID = *req.CraftingMethodID
+	}
+	if req.IsDefault != nil {
+		existingRecipe.IsDefault = *req.IsDefault
+	}
+	if req.Inputs != nil {
+		if existingRecipe.Inputs, err = buildRecipeInputs(*req.Inputs); err != nil {
+			return nil, err
+		}
+	}
+	if req.Outputs != nil {
+		if existingRecipe.Outputs, err = buildRecipeOutputs(*req.Outputs); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateRecipeEnergy(existingRecipe.EUPerTick, existingRecipe.DurationTicks); err != nil {
+		return nil, err
+	}
+	if err := s.cycleChecker.CheckRecipe(ctx, existingRecipe); err != nil {
+		return nil, fmt.Errorf("cannot update recipe: %w", err)
+	}
+
+	// 3. Store the updated recipe, replacing inputs and outputs atomically
+	err = s.recipeStore.UpdateRecipe(ctx, existingRecipe)
+	if err != nil {
+		if errors.Is(err, storage.ErrDuplicateEntry) || errors.Is(err, storage.ErrInvalidReference) {
+			return nil, fmt.Errorf("failed to update recipe: %w", err)
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("failed to update recipe, inconsistency detected: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store updated recipe: %w", err)
+	}
+
+	updatedRecipe, fetchErr := s.recipeStore.GetRecipeByID(ctx, id)
+	if fetchErr != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch recipe immediately after update", "recipe_id", id, "error", fetchErr)
+		return existingRecipe, nil
+	}
+
+	return updatedRecipe, nil
+}
+
+// --- DeleteRecipe ---
+func (s *recipeServiceImpl) DeleteRecipe(ctx context.Context, id uint64) error {
+	err := s.recipeStore.DeleteRecipe(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("cannot delete recipe: %w", err)
+		}
+		return fmt.Errorf("failed to delete recipe: %w", err)
+	}
+	return nil
+}
+
+// GetRecipeByID retrieves a recipe with its inputs and outputs using the storage layer.
+func (s *recipeServiceImpl) GetRecipeByID(ctx context.Context, id uint64) (*domain.Recipe, error) {
+	recipe, err := s.recipeStore.GetRecipeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("recipe with id %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	return recipe, nil
+}
+
+// ListRecipes retrieves a paginated list of recipes using the storage layer
+// and constructs the PaginatedResponse.
+func (s *recipeServiceImpl) ListRecipes(
+	ctx context.Context,
+	params pagination.ListParams[domain.RecipeFilters],
+) (pagination.PaginatedResponse[domain.Recipe], error) {
+	recipes, total, err := s.recipeStore.ListRecipes(ctx, params)
+	if err != nil {
+		return pagination.PaginatedResponse[domain.Recipe]{}, fmt.Errorf("failed to list recipes: %w", err)
+	}
+
+	return pagination.NewPaginatedResponse(recipes, total, params.Page, params.PerPage), nil
+}
+
+// ListCycles reports every dependency cycle in the stored recipes.
+func (s *recipeServiceImpl) ListCycles(ctx context.Context) (*domain.RecipeCycleReport, error) {
+	graph, err := recipegraph.Load(ctx, s.recipeStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipe cycles: %w", err)
+	}
+
+	cycles, truncated := graph.Cycles(maxReportedCycles)
+	report := &domain.RecipeCycleReport{
+		Components: graph.StronglyConnectedComponents(),
+		Cycles:     cycles,
+		Truncated:  truncated,
+	}
+	// Keep the JSON output as arrays rather than null
+	if report.Components == nil {
+		report.Components = [][]uint64{}
+	}
+	if report.Cycles == nil {
+		report.Cycles = []domain.RecipeCycle{}
+	}
+
+	return report, nil
+}
+
+// CalculateYield models each chanced output as a binomial distribution over the runs:
+// every run independently produces the output's quantity with probability chance/10000.
+func (s *recipeServiceImpl) CalculateYield(ctx context.Context, id uint64, req YieldRequest) (*domain.RecipeYield, error) {
+	recipe, err := s.GetRecipeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	confidence := defaultYieldConfidence
+	if req.Confidence != nil {
+		confidence = *req.Confidence
+	}
+
+	result := &domain.RecipeYield{
+		RecipeID:   recipe.ID,
+		Runs:       req.Runs,
+		Confidence: confidence,
+		Outputs:    make([]domain.OutputYield, 0, len(recipe.Outputs)),
+	}
+
+	var target *domain.RecipeOutput
+	for i, output := range recipe.Outputs {
+		p := float64(output.Chance) / domain.ChanceGuaranteed
+		qty := float64(output.Quantity)
+		variance := qty * qty * binomial.Variance(req.Runs, p)
+
+		result.Outputs = append(result.Outputs, domain.OutputYield{
+			ItemID:               output.ItemID,
+			QuantityPerRun:       output.Quantity,
+			Chance:               output.Chance,
+			Probability:          p,
+			IsPrimaryOutput:      output.IsPrimaryOutput,
+			ExpectedQuantity:     qty * binomial.Mean(req.Runs, p),
+			Variance:             variance,
+			StandardDeviation:    math.Sqrt(variance),
+			MaxQuantity:          uint64(output.Quantity) * req.Runs,
+			QuantityAtConfidence: uint64(output.Quantity) * binomial.AtLeast(req.Runs, p, confidence),
+		})
+
+		if req.TargetItemID != nil && output.ItemID == *req.TargetItemID {
+			target = &recipe.Outputs[i]
+		}
+	}
+
+	if req.TargetItemID != nil {
+		if target == nil {
+			return nil, fmt.Errorf("%w: recipe %d does not output item %d", ErrValidation, id, *req.TargetItemID)
+		}
+
+		p := float64(target.Chance) / domain.ChanceGuaranteed
+		// Runs are all-or-nothing, so count the successful runs needed rather than items.
+		successes := (*req.TargetQuantity + uint64(target.Quantity) - 1) / uint64(target.Quantity)
+		result.Target = &domain.YieldTarget{
+			ItemID:       target.ItemID,
+			Quantity:     *req.TargetQuantity,
+			ExpectedRuns: float64(successes) / p,
+		}
+		if runs, ok := binomial.TrialsNeeded(successes, p, confidence); ok {
+			result.Target.RunsNeeded = &runs
+		}
+	}
+
+	return result, nil
+}
+
+// List (Generic Interface)
+func (s *recipeServiceImpl) List(ctx context.Context, params pagination.ListParams[domain.RecipeFilters]) (pagination.PaginatedResponse[domain.Recipe], error) {
+	return s.ListRecipes(ctx, params)
+}
+
+// ListByCursor (Generic Interface)
+func (s *recipeServiceImpl) ListByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.RecipeFilters],
+) (pagination.CursorResponse[domain.Recipe], error) {
+	recipes, page, err := s.recipeStore.ListRecipesByCursor(ctx, params)
+	if err != nil {
+		return pagination.CursorResponse[domain.Recipe]{}, fmt.Errorf("failed to list recipes: %w", err)
+	}
+	return pagination.NewCursorResponse(recipes, page, params.PerPage), nil
+}
+
+// ListState (Generic Interface)
+func (s *recipeServiceImpl) ListState(
+	ctx context.Context,
+	params pagination.ListParams[domain.RecipeFilters],
+) (pagination.ListState, error) {
+	state, err := s.recipeStore.GetRecipeListState(ctx, params.Filters)
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("failed to summarize recipes: %w", err)
+	}
+	return state, nil
+}
+
+// --- Recipe part helpers ---
+
+// buildRecipeInputs maps input requests to domain inputs, rejecting repeated items.
+func buildRecipeInputs(reqs []RecipeInputRequest) ([]domain.RecipeInput, error) {
+	inputs := make([]domain.RecipeInput, 0, len(reqs))
+	seen := make(map[uint64]bool, len(reqs))
+	for _, req := range reqs {
+		if seen[req.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is listed more than once in inputs", ErrValidation, req.ItemID)
+		}
+		seen[req.ItemID] = true
+
+		inputs = append(inputs, domain.RecipeInput{
+			InputItemID:   req.ItemID,
+			InputQuantity: req.Quantity,
+		})
+	}
+	return inputs, nil
+}
+
+// buildRecipeOutputs maps output requests to domain outputs, applying defaults.
+// Exactly one output ends up primary: the flagged one, or the first if none is flagged.
+func buildRecipeOutputs(reqs []RecipeOutputRequest) ([]domain.RecipeOutput, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: a recipe needs at least one output", ErrValidation)
+	}
+
+	outputs := make([]domain.RecipeOutput, 0, len(reqs))
+	seen := make(map[uint64]bool, len(reqs))
+	primaryCount := 0
+	for _, req := range reqs {
+		if seen[req.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is listed more than once in outputs", ErrValidation, req.ItemID)
+		}
+		seen[req.ItemID] = true
+
+		output := domain.RecipeOutput{
+			ItemID:          req.ItemID,
+			Quantity:        req.Quantity,
+			Chance:          domain.ChanceGuaranteed,
+			IsPrimaryOutput: req.IsPrimaryOutput,
+		}
+		if output.Quantity == 0 {
+			output.Quantity = 1
+		}
+		if req.Chance != nil {
+			output.Chance = *req.Chance
+		}
+		if output.IsPrimaryOutput {
+			primaryCount++
+		}
+		outputs = append(outputs, output)
+	}
+
+	switch {
+	case primaryCount > 1:
+		return nil, fmt.Errorf("%w: only one output can be the primary output", ErrValidation)
+	case primaryCount == 0:
+		outputs[0].IsPrimaryOutput = true
+	}
+
+	return outputs, nil
+}
+
+// validateRecipeEnergy rejects negative energy or duration values, which the unsigned columns can't hold.
+func validateRecipeEnergy(euPerTick, durationTicks domain.JSONNullInt64) error {
+	if euPerTick.Valid && euPerTick.Int64 < 0 {
+		return fmt.Errorf("%w: eu_per_tick must not be negative", ErrValidation)
+	}
+	if durationTicks.Valid && durationTicks.Int64 < 0 {
+		return fmt.Errorf("%w: duration_ticks must not be negative", ErrValidation)
+	}
+	return nil
+}
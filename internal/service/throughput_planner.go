@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+)
+
+// throughputPlanner sizes a production line that runs continuously at a target rate.
+// Unlike craftingTree it works with fractional rates, so there is no surplus to reuse:
+// every item's demand is the sum of what its consumers draw. Items are expanded in
+// topological order, consumers first, so each item is expanded once with its total demand.
+// A throughputPlanner is built for a single plan and is not safe for concurrent use.
+type throughputPlanner struct {
+	*craftingCatalog
+
+	order   []uint64 // Items in post-order; reversed it puts consumers before their inputs
+	visited map[uint64]bool
+	path    []uint64 // Items currently being visited, used to detect cycles
+
+	demand     map[uint64]float64
+	raw        map[uint64]float64
+	missing    map[uint64]float64
+	byproducts map[uint64]float64
+	steps      map[uint64]*domain.ThroughputStep // Keyed by recipe ID
+	stepsOrder []uint64
+}
+
+func newThroughputPlanner(itemStore storage.ItemStore, resolver *recipeResolver, userID *uint64) *throughputPlanner {
+	return &throughputPlanner{
+		craftingCatalog: newCraftingCatalog(itemStore, resolver, userID),
+		visited:         make(map[uint64]bool),
+		demand:          make(map[uint64]float64),
+		raw:             make(map[uint64]float64),
+		missing:         make(map[uint64]float64),
+		byproducts:      make(map[uint64]float64),
+		steps:           make(map[uint64]*domain.ThroughputStep),
+	}
+}
+
+// plan computes the rates needed to produce perMinute of the item.
+func (p *throughputPlanner) plan(ctx context.Context, itemID uint64, perMinute float64) error {
+	if err := p.visit(ctx, itemID); err != nil {
+		return err
+	}
+
+	p.demand[itemID] = perMinute
+	for i := len(p.order) - 1; i >= 0; i-- {
+		if err := p.expand(ctx, p.order[i]); err != nil {
+			return err
+		}
+	}
+
+	// Byproducts are never expanded, so their details may not be loaded yet
+	for byproductID := range p.byproducts {
+		if _, err := p.item(ctx, byproductID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visit walks the recipe tree below the item depth first, appending each item to
+// order once all of its inputs have been appended.
+func (p *throughputPlanner) visit(ctx context.Context, itemID uint64) error {
+	if p.visited[itemID] {
+		return nil
+	}
+
+	item, err := p.item(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if !item.IsRawMaterial {
+		recipe, err := p.recipe(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if recipe != nil {
+			if err := checkPath(p.path, itemID); err != nil {
+				return err
+			}
+			p.path = append(p.path, itemID)
+			for _, input := range recipe.Inputs {
+				if err := p.visit(ctx, input.InputItemID); err != nil {
+					return err
+				}
+			}
+			p.path = p.path[:len(p.path)-1]
+		}
+	}
+
+	p.visited[itemID] = true
+	p.order = append(p.order, itemID)
+	return nil
+}
+
+// expand turns the item's accumulated demand into recipe runs and demand for its inputs.
+func (p *throughputPlanner) expand(ctx context.Context, itemID uint64) error {
+	demand := p.demand[itemID]
+	if demand == 0 {
+		return nil
+	}
+
+	item, err := p.item(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if item.IsRawMaterial {
+		p.raw[itemID] += demand
+		return nil
+	}
+	recipe, err := p.recipe(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if recipe == nil {
+		p.missing[itemID] += demand
+		return nil
+	}
+
+	// Chanced outputs count at their expected rate. No number of runs makes up for a
+	// chance of 0, so such an item is as good as without a recipe.
+	perRun := expectedOutputQuantity(recipe, itemID)
+	if perRun == 0 {
+		p.missing[itemID] += demand
+		return nil
+	}
+	runsPerMinute := demand / perRun
+	p.addStep(recipe, itemID, runsPerMinute)
+
+	for _, output := range recipe.Outputs {
+		if output.ItemID != itemID {
+			p.byproducts[output.ItemID] += runsPerMinute * expectedOutputQuantity(recipe, output.ItemID)
+		}
+	}
+	for _, input := range recipe.Inputs {
+		p.demand[input.InputItemID] += runsPerMinute * float64(input.InputQuantity)
+	}
+
+	return nil
+}
+
+func (p *throughputPlanner) addStep(recipe *domain.Recipe, itemID uint64, runsPerMinute float64) {
+	step, ok := p.steps[recipe.ID]
+	if !ok {
+		step = &domain.ThroughputStep{
+			RecipeID:         recipe.ID,
+			RecipeName:       recipe.Name,
+			CraftingMethodID: recipe.CraftingMethodID,
+			OutputItemID:     itemID,
+			DurationTicks:    recipe.DurationTicks,
+		}
+		p.steps[recipe.ID] = step
+		p.stepsOrder = append(p.stepsOrder, recipe.ID)
+	}
+	step.RunsPerMinute += runsPerMinute
+}
+
+// result assembles the plan. Steps keep the order recipes were first expanded in,
+// which puts every step before the steps feeding it.
+func (p *throughputPlanner) result(itemID uint64, perMinute float64) *domain.ThroughputPlan {
+	plan := &domain.ThroughputPlan{
+		ItemID:           itemID,
+		ItemsPerMinute:   perMinute,
+		Steps:            make([]domain.ThroughputStep, 0, len(p.stepsOrder)),
+		ByCraftingMethod: []domain.CraftingMethodThroughput{},
+		RawMaterials:     p.itemRates(p.raw),
+		Byproducts:       p.itemRates(p.byproducts),
+		MissingRecipes:   p.itemRates(p.missing),
+	}
+
+	type methodTotals struct {
+		machines       float64
+		machinesNeeded uint64
+		inputs         map[uint64]float64
+		outputs        map[uint64]float64
+	}
+	methods := make(map[uint64]*methodTotals)
+
+	for _, recipeID := range p.stepsOrder {
+		step := p.steps[recipeID]
+		recipe := p.recipes[step.OutputItemID]
+
+		inputs := make(map[uint64]float64, len(recipe.Inputs))
+		for _, input := range recipe.Inputs {
+			inputs[input.InputItemID] += step.RunsPerMinute * float64(input.InputQuantity)
+		}
+		outputs := make(map[uint64]float64, len(recipe.Outputs))
+		for _, output := range recipe.Outputs {
+			outputs[output.ItemID] += step.RunsPerMinute * expectedOutputQuantity(recipe, output.ItemID)
+		}
+		step.Inputs = p.itemRates(inputs)
+		step.Outputs = p.itemRates(outputs)
+
+		totals, ok := methods[step.CraftingMethodID]
+		if !ok {
+			totals = &methodTotals{inputs: map[uint64]float64{}, outputs: map[uint64]float64{}}
+			methods[step.CraftingMethodID] = totals
+		}
+		if step.DurationTicks.Valid {
+			// One machine completes TicksPerMinute / duration runs a minute
+			machines := step.RunsPerMinute * float64(step.DurationTicks.Int64) / domain.TicksPerMinute
+			machinesNeeded := uint64(math.Ceil(machines))
+			step.Machines = &machines
+			step.MachinesNeeded = &machinesNeeded
+			totals.machines += machines
+			totals.machinesNeeded += machinesNeeded
+		}
+		for id, rate := range inputs {
+			totals.inputs[id] += rate
+		}
+		for id, rate := range outputs {
+			totals.outputs[id] += rate
+		}
+
+		plan.Steps = append(plan.Steps, *step)
+	}
+
+	for methodID, totals := range methods {
+		plan.ByCraftingMethod = append(plan.ByCraftingMethod, domain.CraftingMethodThroughput{
+			CraftingMethodID: methodID,
+			Machines:         totals.machines,
+			MachinesNeeded:   totals.machinesNeeded,
+			InputRates:       p.itemRates(totals.inputs),
+			OutputRates:      p.itemRates(totals.outputs),
+		})
+	}
+	sort.Slice(plan.ByCraftingMethod, func(i, j int) bool {
+		return plan.ByCraftingMethod[i].CraftingMethodID < plan.ByCraftingMethod[j].CraftingMethodID
+	})
+
+	return plan
+}
+
+// itemRates converts an item ID -> rate map into a slice sorted by item ID, dropping zero rates.
+func (p *throughputPlanner) itemRates(rates map[uint64]float64) []domain.ItemRate {
+	result := make([]domain.ItemRate, 0, len(rates))
+	for itemID, rate := range rates {
+		if rate == 0 {
+			continue
+		}
+		entry := domain.ItemRate{ItemID: itemID, PerMinute: rate}
+		if item, ok := p.items[itemID]; ok {
+			entry.Name = item.Name
+			entry.Slug = item.Slug
+		}
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ItemID < result[j].ItemID })
+	return result
+}
+
+// expectedOutputQuantity returns how many units of the item one run of the recipe produces on average.
+func expectedOutputQuantity(recipe *domain.Recipe, itemID uint64) float64 {
+	for _, output := range recipe.Outputs {
+		if output.ItemID == itemID && output.Quantity > 0 {
+			return float64(output.Quantity) * float64(output.Chance) / domain.ChanceGuaranteed
+		}
+	}
+	return 1
+}
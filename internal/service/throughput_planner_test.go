@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/recipegraph"
+)
+
+// Items of the test catalog
+const (
+	itemCircuit = 1 // Crafted from plates and gems
+	itemPlate   = 2 // Crafted from ore, half the time, with slag on the side
+	itemOre     = 3 // Raw
+	itemGem     = 4 // Not raw, but nothing produces it
+	itemSlag    = 5
+	itemBoard   = 7 // Crafted from plates and wires
+	itemWire    = 8 // Crafted from plates
+	itemLoopA   = 9
+	itemLoopB   = 11
+	itemUntimed = 12 // Crafted from ore without a duration
+)
+
+// newTestThroughputPlanner returns a planner whose catalog is filled in advance with the
+// test items and the given recipes, keyed by output item, so it never reaches for a store.
+func newTestThroughputPlanner(recipes map[uint64]*domain.Recipe) *throughputPlanner {
+	p := newThroughputPlanner(nil, nil, nil)
+	for _, id := range []uint64{itemCircuit, itemPlate, itemOre, itemGem, itemSlag, itemBoard, itemWire, itemLoopA, itemLoopB, itemUntimed} {
+		p.items[id] = &domain.Item{ID: id, IsRawMaterial: id == itemOre}
+	}
+	for itemID, recipe := range recipes {
+		p.recipes[itemID] = recipe
+	}
+	return p
+}
+
+// testRates flattens item rates for comparison.
+func testRates(itemRates []domain.ItemRate) map[uint64]float64 {
+	result := map[uint64]float64{}
+	for _, rate := range itemRates {
+		result[rate.ItemID] = rate.PerMinute
+	}
+	return result
+}
+
+func TestThroughputPlanner(t *testing.T) {
+	type step struct {
+		recipeID      uint64
+		runsPerMinute float64
+		machines      float64 // -1 when the recipe has no duration
+	}
+	tests := []struct {
+		name           string
+		itemID         uint64
+		perMinute      float64
+		recipes        map[uint64]*domain.Recipe // Selected recipe per output item, nil for none
+		wantSteps      []step
+		wantMachines   map[uint64]uint64 // Machines needed per crafting method
+		wantRaw        map[uint64]float64
+		wantByproducts map[uint64]float64
+		wantMissing    map[uint64]float64
+	}{
+		{
+			name:           "raw material",
+			itemID:         itemOre,
+			perMinute:      10,
+			wantMachines:   map[uint64]uint64{},
+			wantRaw:        map[uint64]float64{itemOre: 10},
+			wantByproducts: map[uint64]float64{},
+			wantMissing:    map[uint64]float64{},
+		},
+		{
+			name:      "no recipe",
+			itemID:    itemGem,
+			perMinute: 3,
+			recipes: map[uint64]*domain.Recipe{
+				itemGem: nil,
+			},
+			wantMachines:   map[uint64]uint64{},
+			wantRaw:        map[uint64]float64{},
+			wantByproducts: map[uint64]float64{},
+			wantMissing:    map[uint64]float64{itemGem: 3},
+		},
+		{
+			// 30 runs make 60 circuits from 90 plates; at a 50% chance that takes 180 plate runs
+			name:      "chanced input with a byproduct",
+			itemID:    itemCircuit,
+			perMinute: 60,
+			recipes: map[uint64]*domain.Recipe{
+				itemCircuit: {
+					ID: 10, CraftingMethodID: 100,
+					DurationTicks: domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: 200, Valid: true}},
+					Outputs:       []domain.RecipeOutput{{ItemID: itemCircuit, Quantity: 2, Chance: domain.ChanceGuaranteed}},
+					Inputs:        []domain.RecipeInput{{InputItemID: itemPlate, InputQuantity: 3}, {InputItemID: itemGem, InputQuantity: 1}},
+				},
+				itemPlate: {
+					ID: 20, CraftingMethodID: 200,
+					DurationTicks: domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: 100, Valid: true}},
+					Outputs: []domain.RecipeOutput{
+						{ItemID: itemPlate, Quantity: 1, Chance: 5000},
+						{ItemID: itemSlag, Quantity: 1, Chance: domain.ChanceGuaranteed},
+					},
+					Inputs: []domain.RecipeInput{{InputItemID: itemOre, InputQuantity: 2}},
+				},
+				itemGem: nil,
+			},
+			wantSteps:      []step{{10, 30, 5}, {20, 180, 15}},
+			wantMachines:   map[uint64]uint64{100: 5, 200: 15},
+			wantRaw:        map[uint64]float64{itemOre: 360},
+			wantByproducts: map[uint64]float64{itemSlag: 180},
+			wantMissing:    map[uint64]float64{itemGem: 30},
+		},
+		{
+			// Boards and wires both draw plates, which are planned once for the total
+			name:      "shared input",
+			itemID:    itemBoard,
+			perMinute: 10,
+			recipes: map[uint64]*domain.Recipe{
+				itemBoard: {
+					ID: 70, CraftingMethodID: 100,
+					DurationTicks: domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: 600, Valid: true}},
+					Outputs:       []domain.RecipeOutput{{ItemID: itemBoard, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+					Inputs:        []domain.RecipeInput{{InputItemID: itemPlate, InputQuantity: 1}, {InputItemID: itemWire, InputQuantity: 1}},
+				},
+				itemWire: {
+					ID: 80, CraftingMethodID: 200,
+					DurationTicks: domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: 50, Valid: true}},
+					Outputs:       []domain.RecipeOutput{{ItemID: itemWire, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+					Inputs:        []domain.RecipeInput{{InputItemID: itemPlate, InputQuantity: 1}},
+				},
+				itemPlate: {
+					ID: 20, CraftingMethodID: 200,
+					DurationTicks: domain.JSONNullInt64{NullInt64: sql.NullInt64{Int64: 100, Valid: true}},
+					Outputs: []domain.RecipeOutput{
+						{ItemID: itemPlate, Quantity: 1, Chance: 5000},
+						{ItemID: itemSlag, Quantity: 1, Chance: domain.ChanceGuaranteed},
+					},
+					Inputs: []domain.RecipeInput{{InputItemID: itemOre, InputQuantity: 2}},
+				},
+			},
+			wantSteps:      []step{{70, 10, 5}, {80, 10, 0.4166666666666667}, {20, 40, 3.3333333333333335}},
+			wantMachines:   map[uint64]uint64{100: 5, 200: 5},
+			wantRaw:        map[uint64]float64{itemOre: 80},
+			wantByproducts: map[uint64]float64{itemSlag: 40},
+			wantMissing:    map[uint64]float64{},
+		},
+		{
+			name:      "no duration",
+			itemID:    itemUntimed,
+			perMinute: 2,
+			recipes: map[uint64]*domain.Recipe{
+				itemUntimed: {
+					ID: 120, CraftingMethodID: 300,
+					Outputs: []domain.RecipeOutput{{ItemID: itemUntimed, Quantity: 4, Chance: domain.ChanceGuaranteed}},
+					Inputs:  []domain.RecipeInput{{InputItemID: itemOre, InputQuantity: 1}},
+				},
+			},
+			wantSteps:      []step{{120, 0.5, -1}},
+			wantMachines:   map[uint64]uint64{300: 0},
+			wantRaw:        map[uint64]float64{itemOre: 0.5},
+			wantByproducts: map[uint64]float64{},
+			wantMissing:    map[uint64]float64{},
+		},
+		{
+			name:      "output that never drops",
+			itemID:    itemBoard,
+			perMinute: 6,
+			recipes: map[uint64]*domain.Recipe{
+				itemBoard: {
+					ID: 70, CraftingMethodID: 300,
+					Outputs: []domain.RecipeOutput{{ItemID: itemBoard, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+					Inputs:  []domain.RecipeInput{{InputItemID: itemWire, InputQuantity: 2}},
+				},
+				itemWire: {
+					ID: 80, CraftingMethodID: 300,
+					Outputs: []domain.RecipeOutput{{ItemID: itemWire, Quantity: 3, Chance: 0}},
+					Inputs:  []domain.RecipeInput{{InputItemID: itemOre, InputQuantity: 1}},
+				},
+			},
+			wantSteps:      []step{{70, 6, -1}},
+			wantMachines:   map[uint64]uint64{300: 0},
+			wantRaw:        map[uint64]float64{},
+			wantByproducts: map[uint64]float64{},
+			wantMissing:    map[uint64]float64{itemWire: 12},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestThroughputPlanner(tt.recipes)
+			if err := p.plan(context.Background(), tt.itemID, tt.perMinute); err != nil {
+				t.Fatalf("plan() error = %v", err)
+			}
+			plan := p.result(tt.itemID, tt.perMinute)
+			if _, err := json.Marshal(plan); err != nil {
+				t.Fatalf("encoding the plan: %v", err)
+			}
+
+			var steps []step
+			for _, s := range plan.Steps {
+				machines := -1.0
+				if s.Machines != nil {
+					machines = *s.Machines
+				}
+				steps = append(steps, step{s.RecipeID, s.RunsPerMinute, machines})
+			}
+			if !reflect.DeepEqual(steps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", steps, tt.wantSteps)
+			}
+			machines := map[uint64]uint64{}
+			for _, m := range plan.ByCraftingMethod {
+				machines[m.CraftingMethodID] = m.MachinesNeeded
+			}
+			if !reflect.DeepEqual(machines, tt.wantMachines) {
+				t.Errorf("machines needed = %v, want %v", machines, tt.wantMachines)
+			}
+			if got := testRates(plan.RawMaterials); !reflect.DeepEqual(got, tt.wantRaw) {
+				t.Errorf("raw materials = %v, want %v", got, tt.wantRaw)
+			}
+			if got := testRates(plan.Byproducts); !reflect.DeepEqual(got, tt.wantByproducts) {
+				t.Errorf("byproducts = %v, want %v", got, tt.wantByproducts)
+			}
+			if got := testRates(plan.MissingRecipes); !reflect.DeepEqual(got, tt.wantMissing) {
+				t.Errorf("missing recipes = %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestThroughputPlannerCycle(t *testing.T) {
+	p := newTestThroughputPlanner(map[uint64]*domain.Recipe{
+		itemLoopA: {
+			ID: 90, CraftingMethodID: 100,
+			Outputs: []domain.RecipeOutput{{ItemID: itemLoopA, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+			Inputs:  []domain.RecipeInput{{InputItemID: itemLoopB, InputQuantity: 1}},
+		},
+		itemLoopB: {
+			ID: 110, CraftingMethodID: 100,
+			Outputs: []domain.RecipeOutput{{ItemID: itemLoopB, Quantity: 1, Chance: domain.ChanceGuaranteed}},
+			Inputs:  []domain.RecipeInput{{InputItemID: itemLoopA, InputQuantity: 1}},
+		},
+	})
+	err := p.plan(context.Background(), itemLoopA, 1)
+
+	var cycleErr *recipegraph.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("plan() error = %v, want a *recipegraph.CycleError", err)
+	}
+	if want := []uint64{itemLoopA, itemLoopB, itemLoopA}; !reflect.DeepEqual(cycleErr.ItemIDs, want) {
+		t.Errorf("CycleError.ItemIDs = %v, want %v", cycleErr.ItemIDs, want)
+	}
+}
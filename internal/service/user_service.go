@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// RegisterRequest defines the payload for creating a user account.
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8"` // At most maxPasswordBytes, checked by Register
+}
+
+// LoginRequest defines the payload for exchanging credentials for a bearer token.
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// UserService defines the interface for user accounts and authentication.
+type UserService interface {
+	Register(ctx context.Context, req RegisterRequest) (*domain.User, error)
+	Login(ctx context.Context, req LoginRequest) (*domain.AuthToken, error)
+	GetUserByID(ctx context.Context, id uint64) (*domain.User, error)
+	// AuthenticateToken verifies a bearer token and returns the user it was issued for.
+	AuthenticateToken(ctx context.Context, token string) (*domain.User, error)
+}
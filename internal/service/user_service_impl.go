@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/dubbie/calculator-api/internal/auth"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenType is the token_type reported to clients; tokens go in an "Authorization: Bearer" header.
+const tokenType = "Bearer"
+
+// maxPasswordBytes is the longest password bcrypt accepts. The limit is in bytes, so
+// passwords with multibyte characters reach it with fewer characters.
+const maxPasswordBytes = 72
+
+// dummyPasswordHash is compared against when logging in with an unknown email, so that
+// the response takes as long as for a registered one and doesn't reveal which emails are.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(fmt.Sprintf("failed to hash dummy password: %v", err))
+	}
+	return hash
+})
+
+// Ensure userServiceImpl implements UserService
+var _ UserService = (*userServiceImpl)(nil)
+
+type userServiceImpl struct {
+	userStore    storage.UserStore
+	tokenManager *auth.TokenManager
+	logger       *slog.Logger
+}
+
+// NewUserService creates a new UserService implementation.
+func NewUserService(userStore storage.UserStore, tokenManager *auth.TokenManager, logger *slog.Logger) UserService {
+	return &userServiceImpl{
+		userStore:    userStore,
+		tokenManager: tokenManager,
+		logger:       logger,
+	}
+}
+
+// --- Register ---
+func (s *userServiceImpl) Register(ctx context.Context, req RegisterRequest) (*domain.User, error) {
+	if len(req.Password) > maxPasswordBytes {
+		return nil, fmt.Errorf("%w: password must be at most %d bytes long", ErrValidation, maxPasswordBytes)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	newUser := &domain.User{
+		Name:     strings.TrimSpace(req.Name),
+		Email:    normalizeEmail(req.Email),
+		Password: string(hash),
+	}
+
+	err = s.userStore.CreateUser(ctx, newUser)
+	if err != nil {
+		if errors.Is(err, storage.ErrDuplicateEntry) {
+			return nil, fmt.Errorf("failed to register user: %w", err)
+		}
+		return nil, fmt.Errorf("failed to store new user: %w", err)
+	}
+
+	if newUser.ID == 0 {
+		return nil, errors.New("failed to retrieve ID after user creation")
+	}
+
+	createdUser, err := s.userStore.GetUserByID(ctx, newUser.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to fetch user immediately after creation", "user_id", newUser.ID, "error", err)
+		return newUser, nil
+	}
+
+	return createdUser, nil
+}
+
+// --- Login ---
+func (s *userServiceImpl) Login(ctx context.Context, req LoginRequest) (*domain.AuthToken, error) {
+	user, err := s.userStore.GetUserByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			// Spend the time a password check takes, whatever its outcome
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(req.Password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to check password: %w", err)
+	}
+
+	token, expiresAt, err := s.tokenManager.Issue(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	return &domain.AuthToken{
+		AccessToken: token,
+		TokenType:   tokenType,
+		ExpiresAt:   expiresAt,
+		User:        user,
+	}, nil
+}
+
+// GetUserByID retrieves a user using the storage layer.
+func (s *userServiceImpl) GetUserByID(ctx context.Context, id uint64) (*domain.User, error) {
+	user, err := s.userStore.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("user with id %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// AuthenticateToken verifies the token and loads its user.
+func (s *userServiceImpl) AuthenticateToken(ctx context.Context, token string) (*domain.User, error) {
+	userID, err := s.tokenManager.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user %d no longer exists", ErrInvalidCredentials, userID)
+		}
+		return nil, fmt.Errorf("failed to load authenticated user: %w", err)
+	}
+	return user, nil
+}
+
+// normalizeEmail lowercases and trims an address so lookups don't depend on how it was typed.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator returns a validator for the request structs of this package. Fields are
+// reported by their JSON names, and optional and nullable fields are validated by the
+// value they hold.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	// Validate merge patch fields by the value they set
+	validate.RegisterCustomTypeFunc(validateOptional,
+		domain.Optional[string]{},
+		domain.Optional[bool]{},
+		domain.Optional[domain.JSONNullString]{},
+	)
+	// Validate nullable fields by their value, null counting as empty
+	validate.RegisterCustomTypeFunc(validateValuer, domain.JSONNullString{})
+
+	return validate
+}
+
+// validateOptional unwraps a domain.Optional for validation.
+func validateOptional(field reflect.Value) any {
+	if optional, ok := field.Interface().(interface{ ValidationValue() any }); ok {
+		return optional.ValidationValue()
+	}
+	return nil
+}
+
+// validateValuer unwraps a nullable SQL wrapper for validation, returning nil for null.
+func validateValuer(field reflect.Value) any {
+	if valuer, ok := field.Interface().(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// FieldErrorMessage describes a failed validation of a field, e.g. "must be a valid URL".
+func FieldErrorMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", err.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", err.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", err.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", err.Param())
+	case "required_with":
+		return fmt.Sprintf("is required when %s is set", err.Param())
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	}
+	return fmt.Sprintf("failed on '%s' validation", err.Tag())
+}
+
+// validationError turns the error of validating a request into an ErrValidation naming
+// every failed field, for callers without a place for structured details.
+func validationError(err error) error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	messages := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		messages[i] = fieldErr.Field() + " " + FieldErrorMessage(fieldErr)
+	}
+	return fmt.Errorf("%w: %s", ErrValidation, strings.Join(messages, ", "))
+}
@@ -11,7 +11,14 @@ import (
 type CraftingMethodStore interface {
 	CreateCraftingMethod(ctx context.Context, method *domain.CraftingMethod) error
 	GetCraftingMethodByID(ctx context.Context, id uint64) (*domain.CraftingMethod, error)
+	GetCraftingMethodBySlug(ctx context.Context, slug string) (*domain.CraftingMethod, error)
+	// ListSlugsWithPrefix returns every slug starting with prefix.
+	ListSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error)
+	// UpdateCraftingMethod writes method if the stored version still equals method.Version
+	// and increments it, otherwise it returns ErrConflict.
 	UpdateCraftingMethod(ctx context.Context, method *domain.CraftingMethod) error
-	DeleteCraftingMethod(ctx context.Context, id uint64) error
+	DeleteCraftingMethod(ctx context.Context, id uint64, version *uint64) error
 	ListCraftingMethods(ctx context.Context, params pagination.ListParams[domain.CraftingMethodFilters]) ([]domain.CraftingMethod, int64, error)
+	ListCraftingMethodsByCursor(ctx context.Context, params pagination.ListParams[domain.CraftingMethodFilters]) ([]domain.CraftingMethod, pagination.CursorPage, error)
+	GetCraftingMethodListState(ctx context.Context, filters domain.CraftingMethodFilters) (pagination.ListState, error)
 }
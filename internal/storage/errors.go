@@ -4,3 +4,6 @@ import "errors"
 
 var ErrNotFound = errors.New("resource not found")
 var ErrDuplicateEntry = errors.New("duplicate entry")
+var ErrInvalidReference = errors.New("referenced resource does not exist")
+var ErrInsufficientQuantity = errors.New("insufficient quantity")
+var ErrConflict = errors.New("resource was modified concurrently")
@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ExportStore reads the whole game dataset.
+type ExportStore interface {
+	// ExportDataset reads every crafting method, item and recipe from one consistent
+	// snapshot, with references resolved to slugs. The order of the rows is unspecified.
+	ExportDataset(ctx context.Context) (*domain.Dataset, error)
+}
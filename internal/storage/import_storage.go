@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ImportStore runs bulk imports of game data.
+type ImportStore interface {
+	// RunImport calls fn with a transaction. The transaction is committed if fn returns
+	// nil and rolled back otherwise, in which case fn's error is returned.
+	RunImport(ctx context.Context, fn func(tx ImportTx) error) error
+}
+
+// ImportTx is the set of reads and writes available within an import transaction.
+type ImportTx interface {
+	// Savepoint calls fn inside a savepoint and rolls back whatever fn wrote if it
+	// returns an error. The surrounding transaction stays usable either way.
+	Savepoint(ctx context.Context, fn func() error) error
+
+	GetCraftingMethodBySlug(ctx context.Context, slug string) (*domain.CraftingMethod, error)
+	CreateCraftingMethod(ctx context.Context, craftingMethod *domain.CraftingMethod) error
+	UpdateCraftingMethod(ctx context.Context, craftingMethod *domain.CraftingMethod) error
+
+	GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error)
+	CreateItem(ctx context.Context, item *domain.Item) error
+	UpdateItem(ctx context.Context, item *domain.Item) error
+
+	// GetRecipeIDByName returns the ID of the recipe with the given name.
+	GetRecipeIDByName(ctx context.Context, name string) (uint64, error)
+	// CreateRecipe and UpdateRecipe write the recipe together with its inputs and outputs.
+	CreateRecipe(ctx context.Context, recipe *domain.Recipe) error
+	UpdateRecipe(ctx context.Context, recipe *domain.Recipe) error
+	ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error)
+}
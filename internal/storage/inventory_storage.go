@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// InventoryStore defines the interface for storing users' item quantities.
+type InventoryStore interface {
+	GetInventoryItem(ctx context.Context, userID, itemID uint64) (*domain.InventoryItem, error)
+	// SetInventoryQuantity creates or replaces the quantity of the item.
+	SetInventoryQuantity(ctx context.Context, userID, itemID, quantity uint64) error
+	// AdjustInventory applies all deltas in one transaction. If any of them would make a
+	// quantity negative nothing is applied and ErrInsufficientQuantity is returned.
+	AdjustInventory(ctx context.Context, userID uint64, deltas []domain.InventoryDelta) error
+	DeleteInventoryItem(ctx context.Context, userID, itemID uint64) error
+	ListInventory(ctx context.Context, params pagination.ListParams[domain.InventoryFilters]) ([]domain.InventoryItem, int64, error)
+	ListInventoryByCursor(ctx context.Context, params pagination.ListParams[domain.InventoryFilters]) ([]domain.InventoryItem, pagination.CursorPage, error)
+	GetInventoryListState(ctx context.Context, filters domain.InventoryFilters) (pagination.ListState, error)
+}
@@ -11,7 +11,14 @@ import (
 type ItemStore interface {
 	CreateItem(ctx context.Context, item *domain.Item) error
 	GetItemByID(ctx context.Context, id uint64) (*domain.Item, error)
+	GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error)
+	// ListSlugsWithPrefix returns every slug starting with prefix.
+	ListSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error)
+	// UpdateItem writes item if the stored version still equals item.Version and increments
+	// it, otherwise it returns ErrConflict.
 	UpdateItem(ctx context.Context, item *domain.Item) error
-	DeleteItem(ctx context.Context, id uint64) error
+	DeleteItem(ctx context.Context, id uint64, version *uint64) error
 	ListItems(ctx context.Context, params pagination.ListParams[domain.ItemFilters]) ([]domain.Item, int64, error)
+	ListItemsByCursor(ctx context.Context, params pagination.ListParams[domain.ItemFilters]) ([]domain.Item, pagination.CursorPage, error)
+	GetItemListState(ctx context.Context, filters domain.ItemFilters) (pagination.ListState, error)
 }
@@ -5,63 +5,40 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/dubbie/calculator-api/internal/app/pagination"
 	"github.com/dubbie/calculator-api/internal/domain"
 	"github.com/dubbie/calculator-api/internal/storage"
-	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
 var _ storage.CraftingMethodStore = (*mysqlCraftingMethodStore)(nil)
 
 type mysqlCraftingMethodStore struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	queryMetrics *QueryMetrics
 }
 
-func NewMySQLCraftingMethodStore(db *sqlx.DB) *mysqlCraftingMethodStore {
+// NewMySQLCraftingMethodStore creates the store. queryMetrics may be nil to leave the store untimed.
+func NewMySQLCraftingMethodStore(db *sqlx.DB, queryMetrics *QueryMetrics) *mysqlCraftingMethodStore {
 	if db == nil {
 		panic("sqlx.DB instance is required")
 	}
-	return &mysqlCraftingMethodStore{db: db}
+	return &mysqlCraftingMethodStore{db: db, queryMetrics: queryMetrics}
 }
 
+// craftingMethodColumns are the columns of a crafting method row.
+const craftingMethodColumns = "id, name, slug, description, version, created_at, updated_at"
+
 func (s *mysqlCraftingMethodStore) CreateCraftingMethod(
 	ctx context.Context,
 	craftingMethod *domain.CraftingMethod,
 ) error {
-	now := time.Now()
-	craftingMethod.CreatedAt = now
-	craftingMethod.UpdatedAt = now
-
-	query := `
-        INSERT INTO crafting_methods (name, slug, description, created_at, updated_at)
-        VALUES (:name, :slug, :description, :created_at, :updated_at);
-	`
-
-	res, err := s.db.NamedExecContext(ctx, query, craftingMethod)
-	if err != nil {
-		// Debug the crafting method
-		fmt.Printf("Crafting Method: %+v\n", craftingMethod)
-		// Check for duplicate entry (MySQL specific error number 1062)
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
-			return fmt.Errorf("crafting method creation failed: %w: %s", storage.ErrDuplicateEntry, err.Error())
-		}
-		return fmt.Errorf("error creating crafting method: %w", err)
-	}
+	defer s.queryMetrics.observe("crafting_method", "CreateCraftingMethod")()
 
-	// Get the ID of the newly created item
-	id, err := res.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("error getting last insert ID after creating crafting method: %w", err)
-	}
-	craftingMethod.ID = uint64(id)
-
-	return nil
+	return insertCraftingMethod(ctx, s.db, craftingMethod)
 }
 
 // GetCraftingMethodByID retrieves a crafting method by its ID.
@@ -69,11 +46,9 @@ func (s *mysqlCraftingMethodStore) GetCraftingMethodByID(
 	ctx context.Context,
 	id uint64,
 ) (*domain.CraftingMethod, error) {
-	query := `
-        SELECT id, name, slug, description, created_at, updated_at
-        FROM crafting_methods
-        WHERE id = ?
-    `
+	defer s.queryMetrics.observe("crafting_method", "GetCraftingMethodByID")()
+
+	query := "SELECT " + craftingMethodColumns + " FROM crafting_methods WHERE id = ?"
 	var craftingMethod domain.CraftingMethod
 
 	err := s.db.GetContext(ctx, &craftingMethod, query, id)
@@ -87,101 +62,188 @@ func (s *mysqlCraftingMethodStore) GetCraftingMethodByID(
 	return &craftingMethod, nil
 }
 
-// UpdateCraftingMethod updates a crafting method.
+// GetCraftingMethodBySlug retrieves a crafting method by its slug.
+func (s *mysqlCraftingMethodStore) GetCraftingMethodBySlug(
+	ctx context.Context,
+	slug string,
+) (*domain.CraftingMethod, error) {
+	defer s.queryMetrics.observe("crafting_method", "GetCraftingMethodBySlug")()
+
+	query := "SELECT " + craftingMethodColumns + " FROM crafting_methods WHERE slug = ?"
+	var craftingMethod domain.CraftingMethod
+
+	err := s.db.GetContext(ctx, &craftingMethod, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching crafting method with slug %q: %w", slug, err)
+	}
+
+	return &craftingMethod, nil
+}
+
+// ListSlugsWithPrefix returns every crafting method slug starting with prefix.
+// Slugs only contain letters, digits and dashes, so prefix needs no LIKE escaping.
+func (s *mysqlCraftingMethodStore) ListSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	defer s.queryMetrics.observe("crafting_method", "ListSlugsWithPrefix")()
+
+	slugs := []string{}
+	if err := s.db.SelectContext(ctx, &slugs, "SELECT slug FROM crafting_methods WHERE slug LIKE ?", prefix+"%"); err != nil {
+		return nil, fmt.Errorf("error listing crafting method slugs with prefix %q: %w", prefix, err)
+	}
+	return slugs, nil
+}
+
+// UpdateCraftingMethod updates a crafting method if its version is still
+// craftingMethod.Version, and increments it. A newer version yields storage.ErrConflict.
 func (s *mysqlCraftingMethodStore) UpdateCraftingMethod(
 	ctx context.Context,
 	craftingMethod *domain.CraftingMethod,
 ) error {
-	craftingMethod.UpdatedAt = time.Now()
+	defer s.queryMetrics.observe("crafting_method", "UpdateCraftingMethod")()
 
-	query := `UPDATE crafting_methods SET
-		        	name = :name,
-		        	slug = :slug,
-		        	description = :description,
-		        	updated_at = :updated_at
-		        WHERE id = :id`
+	return updateCraftingMethod(ctx, s.db, craftingMethod)
+}
+
+// DeleteCraftingMethod deletes a crafting method, if version is set only while it still
+// has that version.
+func (s *mysqlCraftingMethodStore) DeleteCraftingMethod(
+	ctx context.Context,
+	id uint64,
+	version *uint64,
+) error {
+	defer s.queryMetrics.observe("crafting_method", "DeleteCraftingMethod")()
+
+	query, args := "DELETE FROM crafting_methods WHERE id = ?", []any{id}
+	if version != nil {
+		query, args = query+" AND version = ?", append(args, *version)
+	}
 
-	res, err := s.db.NamedExecContext(ctx, query, craftingMethod)
+	res, err := s.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		// Check for duplicate entry error (MySQL specific error number 1062)
-		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
-			return fmt.Errorf("crafting method update failed: %w", err)
-		}
-		return fmt.Errorf("error updating crafting method: %w", err)
+		return fmt.Errorf("error deleting crafting method with id %d: %w", id, err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error checking rows affected after updating crafting method %d: %w", craftingMethod.ID, err)
+		return fmt.Errorf("error checking rows affected after deleting crafting method: %w", err)
 	}
 
 	if rowsAffected == 0 {
+		if version != nil {
+			return missingOrConflict(ctx, s.db, "crafting_methods", id)
+		}
 		return storage.ErrNotFound
 	}
 
 	return nil
 }
 
-// DeleteCraftingMethod deletes a crafting method.
-func (s *mysqlCraftingMethodStore) DeleteCraftingMethod(
-	ctx context.Context,
-	id uint64,
-) error {
+// insertCraftingMethod writes a new crafting method using db, which may be a transaction.
+func insertCraftingMethod(ctx context.Context, db sqlx.ExtContext, craftingMethod *domain.CraftingMethod) error {
+	now := time.Now()
+	craftingMethod.CreatedAt = now
+	craftingMethod.UpdatedAt = now
+
 	query := `
-        DELETE FROM crafting_methods
-        WHERE id = ?;
+        INSERT INTO crafting_methods (name, slug, description, created_at, updated_at)
+        VALUES (:name, :slug, :description, :created_at, :updated_at);
 	`
 
-	res, err := s.db.ExecContext(ctx, query, id)
+	res, err := sqlx.NamedExecContext(ctx, db, query, craftingMethod)
 	if err != nil {
-		return fmt.Errorf("error deleting crafting method with id %d: %w", id, err)
+		return wrapWriteError("crafting method creation failed", err)
+	}
+
+	// Get the ID of the newly created item
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID after creating crafting method: %w", err)
+	}
+	craftingMethod.ID = uint64(id)
+	craftingMethod.Version = 1
+
+	return nil
+}
+
+// updateCraftingMethod writes the crafting method using db, which may be a transaction,
+// if its version is still craftingMethod.Version, and increments it.
+func updateCraftingMethod(ctx context.Context, db sqlx.ExtContext, craftingMethod *domain.CraftingMethod) error {
+	craftingMethod.UpdatedAt = time.Now()
+
+	query := `UPDATE crafting_methods SET
+		        	name = :name,
+		        	slug = :slug,
+		        	description = :description,
+		        	version = version + 1,
+		        	updated_at = :updated_at
+		        WHERE id = :id AND version = :version`
+
+	res, err := sqlx.NamedExecContext(ctx, db, query, craftingMethod)
+	if err != nil {
+		return wrapWriteError(fmt.Sprintf("crafting method %d update failed", craftingMethod.ID), err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error checking rows affected after deleting crafting method: %w", err)
+		return fmt.Errorf("error checking rows affected after updating crafting method %d: %w", craftingMethod.ID, err)
 	}
 
 	if rowsAffected == 0 {
-		return storage.ErrNotFound
+		return missingOrConflict(ctx, db, "crafting_methods", craftingMethod.ID)
 	}
 
+	craftingMethod.Version++
 	return nil
 }
 
+// craftingMethodSorts lists the fields crafting methods can be sorted by.
+var craftingMethodSorts = listSort[domain.CraftingMethod]{
+	fields: map[string]sortField[domain.CraftingMethod]{
+		"name":       {column: "name", key: func(m domain.CraftingMethod) any { return m.Name }},
+		"slug":       {column: "slug", key: func(m domain.CraftingMethod) any { return m.Slug }},
+		"created_at": {column: "created_at", key: func(m domain.CraftingMethod) any { return m.CreatedAt }},
+		"updated_at": {column: "updated_at", key: func(m domain.CraftingMethod) any { return m.UpdatedAt }},
+	},
+	defaultField: "created_at",
+	defaultDesc:  true,
+	idColumn:     "id",
+	id:           func(m domain.CraftingMethod) uint64 { return m.ID },
+}
+
+// craftingMethodFilters returns the conditions selecting the crafting methods matched by filters.
+func craftingMethodFilters(filters domain.CraftingMethodFilters) squirrel.And {
+	conditions := squirrel.And{}
+	if filters.Name != nil && *filters.Name != "" {
+		// Use LIKE for partial matching, adjust if exact match needed
+		conditions = append(conditions, squirrel.Like{"name": "%" + *filters.Name + "%"})
+	}
+	return conditions
+}
+
 // ListCraftingMethods retrieves a paginated and filtered list of crafting methods.
 func (s *mysqlCraftingMethodStore) ListCraftingMethods(
 	ctx context.Context,
 	params pagination.ListParams[domain.CraftingMethodFilters],
 ) ([]domain.CraftingMethod, int64, error) {
+	defer s.queryMetrics.observe("crafting_method", "ListCraftingMethods")()
+
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)
+	filters := craftingMethodFilters(params.Filters)
 
 	// Base select query for crafting methods
 	selectBuilder := psql.Select(
-		"id", "name", "slug", "description", "created_at", "updated_at",
-	).From("crafting_methods")
+		"id", "name", "slug", "description", "version", "created_at", "updated_at",
+	).From("crafting_methods").Where(filters)
 
 	// Base count query
-	countBuilder := psql.Select("COUNT(*)").From("crafting_methods")
-
-	// Apply filters
-	if params.Filters.Name != nil && *params.Filters.Name != "" {
-		// Use LIKE for partial matching, adjust if exact match needed
-		namePattern := "%" + *params.Filters.Name + "%"
-		selectBuilder = selectBuilder.Where(squirrel.Like{"name": namePattern})
-		countBuilder = countBuilder.Where(squirrel.Like{"name": namePattern})
-	}
+	countBuilder := psql.Select("COUNT(*)").From("crafting_methods").Where(filters)
 
 	// Get total count matching filters before applying limit/offset
-	countQuery, countArgs, err := countBuilder.ToSql()
+	total, err := countRows(ctx, s.db, countBuilder, params.Total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error building count query for crafting methods: %w", err)
-	}
-
-	var total int64
-	err = s.db.GetContext(ctx, &total, countQuery, countArgs...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("error executing count query for crafting methods: %w", err)
+		return nil, 0, fmt.Errorf("error counting crafting methods: %w", err)
 	}
 
 	if total == 0 {
@@ -189,22 +251,7 @@ func (s *mysqlCraftingMethodStore) ListCraftingMethods(
 	}
 
 	// Apply sorting
-	sortField, sortOrder := "created_at", "DESC"
-	if params.Sort != "" {
-		parts := strings.Split(params.Sort, "_")
-		if len(parts) == 2 {
-			allowedSortFields := map[string]bool{"name": true, "slug": true, "created_at": true, "updated_at": true}
-			if allowedSortFields[parts[0]] {
-				sortField = parts[0]
-				if strings.ToLower(parts[1]) == "asc" {
-					sortOrder = "ASC"
-				} else if strings.ToLower(parts[1]) == "desc" {
-					sortOrder = "DESC"
-				}
-			}
-		}
-	}
-	selectBuilder = selectBuilder.OrderBy(fmt.Sprintf("%s %s", sortField, sortOrder))
+	selectBuilder = selectBuilder.OrderBy(craftingMethodSorts.orderBy(params.Sort)...)
 
 	// Apply pagination (Limit and Offset)
 	offset := uint64((params.Page - 1) * params.PerPage)
@@ -225,3 +272,40 @@ func (s *mysqlCraftingMethodStore) ListCraftingMethods(
 
 	return craftingMethods, total, nil
 }
+
+// ListCraftingMethodsByCursor retrieves one page of the filtered crafting methods after or
+// before params.Cursor.
+func (s *mysqlCraftingMethodStore) ListCraftingMethodsByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.CraftingMethodFilters],
+) ([]domain.CraftingMethod, pagination.CursorPage, error) {
+	defer s.queryMetrics.observe("crafting_method", "ListCraftingMethodsByCursor")()
+
+	selectBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question).Select(
+		"id", "name", "slug", "description", "version", "created_at", "updated_at",
+	).From("crafting_methods").Where(craftingMethodFilters(params.Filters))
+
+	methods, page, err := selectCursorPage(
+		ctx, s.db, selectBuilder, craftingMethodSorts, params.Sort, params.PerPage, params.Cursor,
+	)
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error listing crafting methods: %w", err)
+	}
+	return methods, page, nil
+}
+
+// GetCraftingMethodListState counts the crafting methods matched by filters and finds their newest change.
+func (s *mysqlCraftingMethodStore) GetCraftingMethodListState(ctx context.Context, filters domain.CraftingMethodFilters) (pagination.ListState, error) {
+	defer s.queryMetrics.observe("crafting_method", "GetCraftingMethodListState")()
+
+	selectBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question).
+		Select().
+		From("crafting_methods").
+		Where(craftingMethodFilters(filters))
+
+	state, err := selectListState(ctx, s.db, selectBuilder, listStateColumns{updatedAt: []string{"updated_at"}, versions: []string{"version"}})
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error summarizing crafting methods: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// MySQL server error numbers the stores translate into storage errors.
+const (
+	mysqlErrDuplicateEntry  = 1062
+	mysqlErrNoReferencedRow = 1452 // Cannot add or update a child row: a foreign key constraint fails
+)
+
+// isMySQLError reports whether err wraps a MySQL server error with the given number.
+func isMySQLError(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
+
+// wrapWriteError maps MySQL constraint violations to storage errors.
+func wrapWriteError(message string, err error) error {
+	if isMySQLError(err, mysqlErrDuplicateEntry) {
+		return fmt.Errorf("%s: %w: %s", message, storage.ErrDuplicateEntry, err.Error())
+	}
+	if isMySQLError(err, mysqlErrNoReferencedRow) {
+		return fmt.Errorf("%s: %w: %s", message, storage.ErrInvalidReference, err.Error())
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// missingOrConflict explains why a write conditioned on the version of the row with id
+// in table matched nothing: the row is gone, or another write changed its version.
+func missingOrConflict(ctx context.Context, db sqlx.QueryerContext, table string, id uint64) error {
+	var exists bool
+	if err := sqlx.GetContext(ctx, db, &exists, "SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = ?)", id); err != nil {
+		return fmt.Errorf("error checking whether %s row %d exists: %w", table, id, err)
+	}
+	if exists {
+		return storage.ErrConflict
+	}
+	return storage.ErrNotFound
+}
@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlExportStore implements ExportStore interface
+var _ storage.ExportStore = (*mysqlExportStore)(nil)
+
+type mysqlExportStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLExportStore(db *sqlx.DB) *mysqlExportStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlExportStore{db: db}
+}
+
+type exportCraftingMethodRow struct {
+	Slug        string                `db:"slug"`
+	Name        string                `db:"name"`
+	Description domain.JSONNullString `db:"description"`
+}
+
+type exportItemRow struct {
+	Slug          string                `db:"slug"`
+	Name          string                `db:"name"`
+	IsRawMaterial bool                  `db:"is_raw_material"`
+	Description   domain.JSONNullString `db:"description"`
+	ImageURL      domain.JSONNullString `db:"image_url"`
+}
+
+type exportRecipeRow struct {
+	ID             uint64                `db:"id"`
+	Name           domain.JSONNullString `db:"name"`
+	CraftingMethod string                `db:"crafting_method"`
+	EUPerTick      domain.JSONNullInt64  `db:"eu_per_tick"`
+	DurationTicks  domain.JSONNullInt64  `db:"duration_ticks"`
+	Notes          domain.JSONNullString `db:"notes"`
+	IsDefault      bool                  `db:"is_default"`
+}
+
+type exportRecipeInputRow struct {
+	RecipeID uint64 `db:"recipe_id"`
+	Item     string `db:"item"`
+	Quantity uint32 `db:"input_quantity"`
+}
+
+type exportRecipeOutputRow struct {
+	RecipeID        uint64 `db:"recipe_id"`
+	Item            string `db:"item"`
+	Quantity        uint32 `db:"quantity"`
+	Chance          uint32 `db:"chance"`
+	IsPrimaryOutput bool   `db:"is_primary_output"`
+}
+
+// ExportDataset reads all tables inside one read-only transaction so the export is
+// consistent even while the data is being edited.
+func (s *mysqlExportStore) ExportDataset(ctx context.Context) (*domain.Dataset, error) {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction for export: %w", err)
+	}
+	defer tx.Rollback() // Nothing to commit
+
+	var methods []exportCraftingMethodRow
+	if err := tx.SelectContext(ctx, &methods, "SELECT slug, name, description FROM crafting_methods"); err != nil {
+		return nil, fmt.Errorf("error exporting crafting methods: %w", err)
+	}
+
+	var items []exportItemRow
+	if err := tx.SelectContext(ctx, &items, "SELECT slug, name, is_raw_material, description, image_url FROM items"); err != nil {
+		return nil, fmt.Errorf("error exporting items: %w", err)
+	}
+
+	var recipes []exportRecipeRow
+	err = tx.SelectContext(ctx, &recipes, `
+		SELECT r.id, r.name, cm.slug AS crafting_method, r.eu_per_tick, r.duration_ticks, r.notes, r.is_default
+		FROM recipes r
+		JOIN crafting_methods cm ON cm.id = r.crafting_method_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting recipes: %w", err)
+	}
+
+	var inputs []exportRecipeInputRow
+	err = tx.SelectContext(ctx, &inputs, `
+		SELECT ri.recipe_id, i.slug AS item, ri.input_quantity
+		FROM recipe_inputs ri
+		JOIN items i ON i.id = ri.input_item_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting recipe inputs: %w", err)
+	}
+
+	var outputs []exportRecipeOutputRow
+	err = tx.SelectContext(ctx, &outputs, `
+		SELECT ro.recipe_id, i.slug AS item, ro.quantity, ro.chance, ro.is_primary_output
+		FROM recipe_outputs ro
+		JOIN items i ON i.id = ro.item_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting recipe outputs: %w", err)
+	}
+
+	ds := &domain.Dataset{
+		CraftingMethods: make([]domain.DatasetCraftingMethod, 0, len(methods)),
+		Items:           make([]domain.DatasetItem, 0, len(items)),
+		Recipes:         make([]domain.DatasetRecipe, 0, len(recipes)),
+	}
+	for _, m := range methods {
+		ds.CraftingMethods = append(ds.CraftingMethods, domain.DatasetCraftingMethod(m))
+	}
+	for _, i := range items {
+		ds.Items = append(ds.Items, domain.DatasetItem(i))
+	}
+
+	byID := make(map[uint64]int, len(recipes))
+	for _, r := range recipes {
+		byID[r.ID] = len(ds.Recipes)
+		ds.Recipes = append(ds.Recipes, domain.DatasetRecipe{
+			Name:           r.Name,
+			CraftingMethod: r.CraftingMethod,
+			EUPerTick:      r.EUPerTick,
+			DurationTicks:  r.DurationTicks,
+			Notes:          r.Notes,
+			IsDefault:      r.IsDefault,
+			Inputs:         []domain.DatasetRecipeInput{},
+			Outputs:        []domain.DatasetRecipeOutput{},
+		})
+	}
+	for _, in := range inputs {
+		recipe := &ds.Recipes[byID[in.RecipeID]]
+		recipe.Inputs = append(recipe.Inputs, domain.DatasetRecipeInput{Item: in.Item, Quantity: in.Quantity})
+	}
+	for _, out := range outputs {
+		recipe := &ds.Recipes[byID[out.RecipeID]]
+		recipe.Outputs = append(recipe.Outputs, domain.DatasetRecipeOutput{
+			Item:            out.Item,
+			Quantity:        out.Quantity,
+			Chance:          out.Chance,
+			IsPrimaryOutput: out.IsPrimaryOutput,
+		})
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,157 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlImportStore implements ImportStore interface
+var _ storage.ImportStore = (*mysqlImportStore)(nil)
+
+// Ensure mysqlImportTx implements ImportTx interface
+var _ storage.ImportTx = (*mysqlImportTx)(nil)
+
+type mysqlImportStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLImportStore(db *sqlx.DB) *mysqlImportStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlImportStore{db: db}
+}
+
+// RunImport runs fn in a transaction, committing only if it succeeds.
+func (s *mysqlImportStore) RunImport(ctx context.Context, fn func(tx storage.ImportTx) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for import: %w", err)
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if err := fn(&mysqlImportTx{tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing import: %w", err)
+	}
+
+	return nil
+}
+
+// mysqlImportTx implements ImportTx on top of a single transaction.
+type mysqlImportTx struct {
+	tx         *sqlx.Tx
+	savepoints int // Used to name savepoints uniquely
+}
+
+// Savepoint runs fn between SAVEPOINT and RELEASE, rolling back to the savepoint if fn fails.
+func (t *mysqlImportTx) Savepoint(ctx context.Context, fn func() error) error {
+	t.savepoints++
+	name := fmt.Sprintf("import_row_%d", t.savepoints)
+
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("error creating savepoint %s: %w", name, err)
+	}
+
+	fnErr := fn()
+	if fnErr != nil {
+		if _, err := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+			return fmt.Errorf("error rolling back to savepoint %s after %v: %w", name, fnErr, err)
+		}
+	}
+
+	if _, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("error releasing savepoint %s: %w", name, err)
+	}
+
+	return fnErr
+}
+
+// GetCraftingMethodBySlug retrieves and locks a crafting method by its slug.
+func (t *mysqlImportTx) GetCraftingMethodBySlug(ctx context.Context, slug string) (*domain.CraftingMethod, error) {
+	query := "SELECT " + craftingMethodColumns + " FROM crafting_methods WHERE slug = ? FOR UPDATE"
+	var craftingMethod domain.CraftingMethod
+
+	err := t.tx.GetContext(ctx, &craftingMethod, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching crafting method with slug %q: %w", slug, err)
+	}
+
+	return &craftingMethod, nil
+}
+
+// CreateCraftingMethod inserts a crafting method.
+func (t *mysqlImportTx) CreateCraftingMethod(ctx context.Context, craftingMethod *domain.CraftingMethod) error {
+	return insertCraftingMethod(ctx, t.tx, craftingMethod)
+}
+
+// UpdateCraftingMethod updates a crafting method previously read within the transaction.
+func (t *mysqlImportTx) UpdateCraftingMethod(ctx context.Context, craftingMethod *domain.CraftingMethod) error {
+	return updateCraftingMethod(ctx, t.tx, craftingMethod)
+}
+
+// GetItemBySlug retrieves and locks an item by its slug.
+func (t *mysqlImportTx) GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error) {
+	query := "SELECT " + itemColumns + " FROM items WHERE slug = ? FOR UPDATE"
+	var item domain.Item
+
+	err := t.tx.GetContext(ctx, &item, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching item with slug %q: %w", slug, err)
+	}
+
+	return &item, nil
+}
+
+// CreateItem inserts an item.
+func (t *mysqlImportTx) CreateItem(ctx context.Context, item *domain.Item) error {
+	return insertItem(ctx, t.tx, item)
+}
+
+// UpdateItem updates an item previously read within the transaction.
+func (t *mysqlImportTx) UpdateItem(ctx context.Context, item *domain.Item) error {
+	return updateItem(ctx, t.tx, item)
+}
+
+// GetRecipeIDByName retrieves and locks the recipe with the given name.
+func (t *mysqlImportTx) GetRecipeIDByName(ctx context.Context, name string) (uint64, error) {
+	var id uint64
+	err := t.tx.GetContext(ctx, &id, "SELECT id FROM recipes WHERE name = ? FOR UPDATE", name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrNotFound
+		}
+		return 0, fmt.Errorf("error fetching recipe with name %q: %w", name, err)
+	}
+	return id, nil
+}
+
+// CreateRecipe inserts a recipe with its inputs and outputs.
+func (t *mysqlImportTx) CreateRecipe(ctx context.Context, recipe *domain.Recipe) error {
+	return insertRecipe(ctx, t.tx, recipe)
+}
+
+// UpdateRecipe updates a recipe and replaces its inputs and outputs.
+func (t *mysqlImportTx) UpdateRecipe(ctx context.Context, recipe *domain.Recipe) error {
+	return updateRecipe(ctx, t.tx, recipe)
+}
+
+// ListRecipeEdges retrieves the edges of all recipes, including those written by the import so far.
+func (t *mysqlImportTx) ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error) {
+	return listRecipeEdges(ctx, t.tx)
+}
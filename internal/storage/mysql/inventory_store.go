@@ -0,0 +1,251 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// inventoryColumns selects an inventory row joined with its item as "ui" and "i".
+const inventoryColumns = "ui.id, ui.user_id, ui.item_id, i.name AS item_name, i.slug AS item_slug, ui.quantity, ui.created_at, ui.updated_at"
+
+// Ensure mysqlInventoryStore implements InventoryStore interface
+var _ storage.InventoryStore = (*mysqlInventoryStore)(nil)
+
+type mysqlInventoryStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLInventoryStore(db *sqlx.DB) *mysqlInventoryStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlInventoryStore{db: db}
+}
+
+// GetInventoryItem retrieves the user's quantity of an item.
+func (s *mysqlInventoryStore) GetInventoryItem(ctx context.Context, userID, itemID uint64) (*domain.InventoryItem, error) {
+	query := `
+		SELECT ` + inventoryColumns + `
+		FROM user_inventories ui
+		JOIN items i ON i.id = ui.item_id
+		WHERE ui.user_id = ? AND ui.item_id = ?
+	`
+	var entry domain.InventoryItem
+
+	err := s.db.GetContext(ctx, &entry, query, userID, itemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching inventory of user %d for item %d: %w", userID, itemID, err)
+	}
+	return &entry, nil
+}
+
+// SetInventoryQuantity upserts the quantity on the (user_id, item_id) unique key.
+func (s *mysqlInventoryStore) SetInventoryQuantity(ctx context.Context, userID, itemID, quantity uint64) error {
+	query := `
+		INSERT INTO user_inventories (user_id, item_id, quantity)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE quantity = VALUES(quantity), version = version + 1
+	`
+	_, err := s.db.ExecContext(ctx, query, userID, itemID, quantity)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return fmt.Errorf("setting inventory quantity failed: %w: %s", storage.ErrInvalidReference, err.Error())
+		}
+		return fmt.Errorf("error setting inventory of user %d for item %d: %w", userID, itemID, err)
+	}
+	return nil
+}
+
+// AdjustInventory applies the deltas in order inside a transaction. Each delta is a
+// single atomic statement, so concurrent adjustments never lose updates.
+func (s *mysqlInventoryStore) AdjustInventory(ctx context.Context, userID uint64, deltas []domain.InventoryDelta) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for inventory adjustment: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, d := range deltas {
+		if err := adjustInventoryItem(ctx, tx, userID, d); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing inventory adjustment: %w", err)
+	}
+	return nil
+}
+
+// DeleteInventoryItem removes the item from the user's inventory.
+func (s *mysqlInventoryStore) DeleteInventoryItem(ctx context.Context, userID, itemID uint64) error {
+	query := "DELETE FROM user_inventories WHERE user_id = ? AND item_id = ?"
+	res, err := s.db.ExecContext(ctx, query, userID, itemID)
+	if err != nil {
+		return fmt.Errorf("error deleting inventory of user %d for item %d: %w", userID, itemID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting inventory item: %w", err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// inventorySorts lists the fields inventory entries can be sorted by, e.g. "item_name_asc".
+var inventorySorts = listSort[domain.InventoryItem]{
+	fields: map[string]sortField[domain.InventoryItem]{
+		"item_name":  {column: "i.name", key: func(e domain.InventoryItem) any { return e.ItemName }},
+		"quantity":   {column: "ui.quantity", key: func(e domain.InventoryItem) any { return e.Quantity }},
+		"created_at": {column: "ui.created_at", key: func(e domain.InventoryItem) any { return e.CreatedAt }},
+		"updated_at": {column: "ui.updated_at", key: func(e domain.InventoryItem) any { return e.UpdatedAt }},
+	},
+	defaultField: "item_name",
+	defaultDesc:  false,
+	idColumn:     "ui.id",
+	id:           func(e domain.InventoryItem) uint64 { return e.ID },
+}
+
+// inventoryFilters returns the conditions selecting the inventory entries matched by filters.
+func inventoryFilters(filters domain.InventoryFilters) sq.And {
+	conditions := sq.And{sq.Eq{"ui.user_id": filters.UserID}}
+	if filters.ItemName != nil && *filters.ItemName != "" {
+		conditions = append(conditions, sq.Like{"i.name": "%" + *filters.ItemName + "%"})
+	}
+	return conditions
+}
+
+// ListInventory retrieves a paginated list of the user's inventory.
+func (s *mysqlInventoryStore) ListInventory(ctx context.Context, params pagination.ListParams[domain.InventoryFilters]) ([]domain.InventoryItem, int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	filters := inventoryFilters(params.Filters)
+
+	selectBuilder := psql.Select(strings.Split(inventoryColumns, ", ")...).
+		From("user_inventories ui").
+		Join("items i ON i.id = ui.item_id").
+		Where(filters)
+	countBuilder := psql.Select("COUNT(*)").
+		From("user_inventories ui").
+		Join("items i ON i.id = ui.item_id").
+		Where(filters)
+
+	total, err := countRows(ctx, s.db, countBuilder, params.Total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting inventory: %w", err)
+	}
+
+	if total == 0 {
+		return []domain.InventoryItem{}, 0, nil
+	}
+
+	selectBuilder = selectBuilder.OrderBy(inventorySorts.orderBy(params.Sort)...)
+
+	offset := uint64((params.Page - 1) * params.PerPage)
+	selectBuilder = selectBuilder.Limit(uint64(params.PerPage)).Offset(offset)
+
+	inventoryQuery, inventoryArgs, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error building select query for inventory: %w", err)
+	}
+
+	entries := []domain.InventoryItem{}
+	err = s.db.SelectContext(ctx, &entries, inventoryQuery, inventoryArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error executing select query for inventory: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// ListInventoryByCursor retrieves one page of the user's inventory after or before params.Cursor.
+func (s *mysqlInventoryStore) ListInventoryByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.InventoryFilters],
+) ([]domain.InventoryItem, pagination.CursorPage, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select(strings.Split(inventoryColumns, ", ")...).
+		From("user_inventories ui").
+		Join("items i ON i.id = ui.item_id").
+		Where(inventoryFilters(params.Filters))
+
+	entries, page, err := selectCursorPage(ctx, s.db, selectBuilder, inventorySorts, params.Sort, params.PerPage, params.Cursor)
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error listing inventory: %w", err)
+	}
+	return entries, page, nil
+}
+
+// GetInventoryListState counts the entries of the user's inventory matched by filters and finds
+// their newest change. Renaming an item changes the entries showing it too.
+func (s *mysqlInventoryStore) GetInventoryListState(ctx context.Context, filters domain.InventoryFilters) (pagination.ListState, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select().
+		From("user_inventories ui").
+		Join("items i ON i.id = ui.item_id").
+		Where(inventoryFilters(filters))
+
+	state, err := selectListState(ctx, s.db, selectBuilder, listStateColumns{
+		updatedAt: []string{"ui.updated_at", "i.updated_at"},
+		versions:  []string{"ui.version", "i.version"},
+	})
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error summarizing inventory: %w", err)
+	}
+	return state, nil
+}
+
+// adjustInventoryItem applies one delta. Increments upsert the row; decrements only
+// match rows holding enough, so the quantity can never go below zero.
+func adjustInventoryItem(ctx context.Context, tx *sqlx.Tx, userID uint64, d domain.InventoryDelta) error {
+	if d.Delta >= 0 {
+		query := `
+			INSERT INTO user_inventories (user_id, item_id, quantity)
+			VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE quantity = quantity + VALUES(quantity), version = version + 1
+		`
+		if _, err := tx.ExecContext(ctx, query, userID, d.ItemID, d.Delta); err != nil {
+			if isMySQLError(err, mysqlErrNoReferencedRow) {
+				return fmt.Errorf("adjusting inventory failed: %w: %s", storage.ErrInvalidReference, err.Error())
+			}
+			return fmt.Errorf("error adding to inventory of user %d for item %d: %w", userID, d.ItemID, err)
+		}
+		return nil
+	}
+
+	amount := uint64(-d.Delta)
+	query := `
+		UPDATE user_inventories
+		SET quantity = quantity - ?, version = version + 1
+		WHERE user_id = ? AND item_id = ? AND quantity >= ?
+	`
+	res, err := tx.ExecContext(ctx, query, amount, userID, d.ItemID, amount)
+	if err != nil {
+		return fmt.Errorf("error removing from inventory of user %d for item %d: %w", userID, d.ItemID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after removing from inventory: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cannot remove %d of item %d: %w", amount, d.ItemID, storage.ErrInsufficientQuantity)
+	}
+
+	return nil
+}
@@ -5,14 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/dubbie/calculator-api/internal/app/pagination"
 	"github.com/dubbie/calculator-api/internal/domain"
 	"github.com/dubbie/calculator-api/internal/storage"
-	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -20,18 +18,116 @@ import (
 var _ storage.ItemStore = (*mysqlItemStore)(nil)
 
 type mysqlItemStore struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	queryMetrics *QueryMetrics
 }
 
-func NewMySQLItemStore(db *sqlx.DB) *mysqlItemStore {
+// NewMySQLItemStore creates the store. queryMetrics may be nil to leave the store untimed.
+func NewMySQLItemStore(db *sqlx.DB, queryMetrics *QueryMetrics) *mysqlItemStore {
 	if db == nil {
 		panic("sqlx.DB instance is required")
 	}
-	return &mysqlItemStore{db: db}
+	return &mysqlItemStore{db: db, queryMetrics: queryMetrics}
 }
 
+// itemColumns are the columns of an item row.
+const itemColumns = "id, name, slug, is_raw_material, description, image_url, version, created_at, updated_at"
+
 // CreateItem creates a new item in the database.
 func (s *mysqlItemStore) CreateItem(ctx context.Context, item *domain.Item) error {
+	defer s.queryMetrics.observe("item", "CreateItem")()
+
+	return insertItem(ctx, s.db, item)
+}
+
+// GetItemByID retrieves a single item by its ID.
+func (s *mysqlItemStore) GetItemByID(ctx context.Context, id uint64) (*domain.Item, error) {
+	defer s.queryMetrics.observe("item", "GetItemByID")()
+
+	query := "SELECT " + itemColumns + " FROM items WHERE id = ?"
+	var item domain.Item
+
+	err := s.db.GetContext(ctx, &item, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound // Define ErrNotFound in storage package
+		}
+		// Wrap error for context
+		return nil, fmt.Errorf("error fetching item with id %d: %w", id, err)
+	}
+	return &item, nil
+}
+
+// GetItemBySlug retrieves a single item by its slug.
+func (s *mysqlItemStore) GetItemBySlug(ctx context.Context, slug string) (*domain.Item, error) {
+	defer s.queryMetrics.observe("item", "GetItemBySlug")()
+
+	query := "SELECT " + itemColumns + " FROM items WHERE slug = ?"
+	var item domain.Item
+
+	err := s.db.GetContext(ctx, &item, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching item with slug %q: %w", slug, err)
+	}
+	return &item, nil
+}
+
+// ListSlugsWithPrefix returns every item slug starting with prefix.
+// Slugs only contain letters, digits and dashes, so prefix needs no LIKE escaping.
+func (s *mysqlItemStore) ListSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	defer s.queryMetrics.observe("item", "ListSlugsWithPrefix")()
+
+	slugs := []string{}
+	if err := s.db.SelectContext(ctx, &slugs, "SELECT slug FROM items WHERE slug LIKE ?", prefix+"%"); err != nil {
+		return nil, fmt.Errorf("error listing item slugs with prefix %q: %w", prefix, err)
+	}
+	return slugs, nil
+}
+
+// --- UpdateItem ---
+// UpdateItem only writes the item if its version is still item.Version, and increments it.
+// A newer version in the database yields storage.ErrConflict.
+func (s *mysqlItemStore) UpdateItem(ctx context.Context, item *domain.Item) error {
+	defer s.queryMetrics.observe("item", "UpdateItem")()
+
+	return updateItem(ctx, s.db, item)
+}
+
+// --- DeleteItem ---
+// DeleteItem deletes the item, if version is set only while it still has that version.
+func (s *mysqlItemStore) DeleteItem(ctx context.Context, id uint64, version *uint64) error {
+	defer s.queryMetrics.observe("item", "DeleteItem")()
+
+	query, args := "DELETE FROM items WHERE id = ?", []any{id}
+	if version != nil {
+		query, args = query+" AND version = ?", append(args, *version)
+	}
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		// Foreign key constraint errors might occur here if not handled by ON DELETE CASCADE/SET NULL etc.
+		return fmt.Errorf("error deleting item with id %d: %w", id, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting item %d: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		if version != nil {
+			return missingOrConflict(ctx, s.db, "items", id)
+		}
+		// No rows deleted, likely means the item ID didn't exist
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// insertItem writes a new item using db, which may be a transaction.
+func insertItem(ctx context.Context, db sqlx.ExtContext, item *domain.Item) error {
 	now := time.Now()
 	item.CreatedAt = now
 	item.UpdatedAt = now
@@ -41,16 +137,9 @@ func (s *mysqlItemStore) CreateItem(ctx context.Context, item *domain.Item) erro
 		VALUES (:name, :slug, :is_raw_material, :description, :image_url, :created_at, :updated_at);
 	`
 
-	res, err := s.db.NamedExecContext(ctx, query, item)
+	res, err := sqlx.NamedExecContext(ctx, db, query, item)
 	if err != nil {
-		// Debug the item
-		fmt.Printf("Item: %+v\n", item)
-		// Check for duplicate entry error (MySQL specific error number 1062)
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
-			return fmt.Errorf("item creation failed: %w: %s", storage.ErrDuplicateEntry, err.Error())
-		}
-		return fmt.Errorf("error creating item: %w", err)
+		return wrapWriteError("item creation failed", err)
 	}
 
 	// Get the ID of the newly created item
@@ -60,28 +149,14 @@ func (s *mysqlItemStore) CreateItem(ctx context.Context, item *domain.Item) erro
 		return fmt.Errorf("error getting last insert ID after creating item: %w", err)
 	}
 	item.ID = uint64(id) // Update the item struct with the new ID
+	item.Version = 1
 
 	return nil
 }
 
-// GetItemByID retrieves a single item by its ID.
-func (s *mysqlItemStore) GetItemByID(ctx context.Context, id uint64) (*domain.Item, error) {
-	query := "SELECT id, name, slug, is_raw_material, description, image_url, created_at, updated_at FROM items WHERE id = ?"
-	var item domain.Item
-
-	err := s.db.GetContext(ctx, &item, query, id)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, storage.ErrNotFound // Define ErrNotFound in storage package
-		}
-		// Wrap error for context
-		return nil, fmt.Errorf("error fetching item with id %d: %w", id, err)
-	}
-	return &item, nil
-}
-
-// --- UpdateItem ---
-func (s *mysqlItemStore) UpdateItem(ctx context.Context, item *domain.Item) error {
+// updateItem writes the item using db, which may be a transaction, if its version is still
+// item.Version, and increments it.
+func updateItem(ctx context.Context, db sqlx.ExtContext, item *domain.Item) error {
 	// Update the UpdatedAt timestamp before saving
 	item.UpdatedAt = time.Now()
 
@@ -92,17 +167,14 @@ func (s *mysqlItemStore) UpdateItem(ctx context.Context, item *domain.Item) erro
             is_raw_material = :is_raw_material,
             description = :description,
             image_url = :image_url,
+            version = version + 1,
             updated_at = :updated_at
-        WHERE id = :id
+        WHERE id = :id AND version = :version
     `
-	res, err := s.db.NamedExecContext(ctx, query, item)
+	res, err := sqlx.NamedExecContext(ctx, db, query, item)
 	if err != nil {
-		// Check for duplicate entry error on update (e.g., changing name/slug to one that exists)
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
-			return fmt.Errorf("item update failed: %w: %s", storage.ErrDuplicateEntry, err.Error())
-		}
-		return fmt.Errorf("error updating item with id %d: %w", item.ID, err)
+		// Duplicate entries come from changing the slug to one that exists
+		return wrapWriteError(fmt.Sprintf("item %d update failed", item.ID), err)
 	}
 
 	// Check if any row was actually updated
@@ -112,71 +184,63 @@ func (s *mysqlItemStore) UpdateItem(ctx context.Context, item *domain.Item) erro
 		return fmt.Errorf("error checking rows affected after updating item %d: %w", item.ID, err)
 	}
 	if rowsAffected == 0 {
-		// No rows updated, likely means the item ID didn't exist
-		return storage.ErrNotFound
+		// No rows updated, either the item ID didn't exist or another write got there first
+		return missingOrConflict(ctx, db, "items", item.ID)
 	}
 
+	item.Version++
 	return nil
 }
 
-// --- DeleteItem ---
-func (s *mysqlItemStore) DeleteItem(ctx context.Context, id uint64) error {
-	query := "DELETE FROM items WHERE id = ?"
-	res, err := s.db.ExecContext(ctx, query, id)
-	if err != nil {
-		// Foreign key constraint errors might occur here if not handled by ON DELETE CASCADE/SET NULL etc.
-		return fmt.Errorf("error deleting item with id %d: %w", id, err)
-	}
+// itemSorts lists the fields items can be sorted by.
+var itemSorts = listSort[domain.Item]{
+	fields: map[string]sortField[domain.Item]{
+		"name":       {column: "name", key: func(i domain.Item) any { return i.Name }},
+		"slug":       {column: "slug", key: func(i domain.Item) any { return i.Slug }},
+		"created_at": {column: "created_at", key: func(i domain.Item) any { return i.CreatedAt }},
+		"updated_at": {column: "updated_at", key: func(i domain.Item) any { return i.UpdatedAt }},
+	},
+	defaultField: "created_at",
+	defaultDesc:  true,
+	idColumn:     "id",
+	id:           func(i domain.Item) uint64 { return i.ID },
+}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("error checking rows affected after deleting item %d: %w", id, err)
+// itemFilters returns the conditions selecting the items matched by filters.
+func itemFilters(filters domain.ItemFilters) sq.And {
+	conditions := sq.And{}
+	if filters.Name != nil && *filters.Name != "" {
+		// Use LIKE for partial matching, adjust if exact match needed
+		conditions = append(conditions, sq.Like{"name": "%" + *filters.Name + "%"})
 	}
-	if rowsAffected == 0 {
-		// No rows deleted, likely means the item ID didn't exist
-		return storage.ErrNotFound
+	if filters.IsRawMaterial != nil {
+		conditions = append(conditions, sq.Eq{"is_raw_material": *filters.IsRawMaterial})
 	}
-
-	return nil
+	// Add more filters here...
+	return conditions
 }
 
 // ListItems retrieves a paginated and filtered list of items.
 func (s *mysqlItemStore) ListItems(ctx context.Context, params pagination.ListParams[domain.ItemFilters]) ([]domain.Item, int64, error) {
+	defer s.queryMetrics.observe("item", "ListItems")()
+
 	// Use squirrel for building the query to handle filters and pagination dynamically
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	filters := itemFilters(params.Filters)
 
 	// Base select query for items
 	selectBuilder := psql.Select(
 		"id", "name", "slug", "is_raw_material",
-		"description", "image_url", "created_at", "updated_at",
-	).From("items")
+		"description", "image_url", "version", "created_at", "updated_at",
+	).From("items").Where(filters)
 
 	// Base count query
-	countBuilder := psql.Select("COUNT(*)").From("items")
-
-	// Apply filters
-	if params.Filters.Name != nil && *params.Filters.Name != "" {
-		// Use LIKE for partial matching, adjust if exact match needed
-		namePattern := "%" + *params.Filters.Name + "%"
-		selectBuilder = selectBuilder.Where(sq.Like{"name": namePattern})
-		countBuilder = countBuilder.Where(sq.Like{"name": namePattern})
-	}
-	if params.Filters.IsRawMaterial != nil {
-		selectBuilder = selectBuilder.Where(sq.Eq{"is_raw_material": *params.Filters.IsRawMaterial})
-		countBuilder = countBuilder.Where(sq.Eq{"is_raw_material": *params.Filters.IsRawMaterial})
-	}
-	// Add more filters here...
+	countBuilder := psql.Select("COUNT(*)").From("items").Where(filters)
 
 	// Get total count matching filters *before* applying limit/offset
-	countQuery, countArgs, err := countBuilder.ToSql()
-	if err != nil {
-		return nil, 0, fmt.Errorf("error building count query for items: %w", err)
-	}
-
-	var total int64
-	err = s.db.GetContext(ctx, &total, countQuery, countArgs...)
+	total, err := countRows(ctx, s.db, countBuilder, params.Total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error executing count query for items: %w", err)
+		return nil, 0, fmt.Errorf("error counting items: %w", err)
 	}
 
 	if total == 0 {
@@ -185,25 +249,7 @@ func (s *mysqlItemStore) ListItems(ctx context.Context, params pagination.ListPa
 	}
 
 	// Apply sorting
-	sortField := "created_at" // Default sort
-	sortOrder := "DESC"       // Default order
-	if params.Sort != "" {
-		parts := strings.Split(params.Sort, "_")
-		if len(parts) == 2 {
-			// Basic validation: check if field is allowed (e.g., "name", "created_at")
-			allowedSortFields := map[string]bool{"name": true, "slug": true, "created_at": true, "updated_at": true}
-			if allowedSortFields[parts[0]] {
-				sortField = parts[0]
-				if strings.ToLower(parts[1]) == "asc" {
-					sortOrder = "ASC"
-				} else if strings.ToLower(parts[1]) == "desc" {
-					sortOrder = "DESC"
-				}
-				// else stick to default DESC
-			}
-		}
-	}
-	selectBuilder = selectBuilder.OrderBy(fmt.Sprintf("%s %s", sortField, sortOrder))
+	selectBuilder = selectBuilder.OrderBy(itemSorts.orderBy(params.Sort)...)
 
 	// Apply pagination (Limit and Offset)
 	offset := uint64((params.Page - 1) * params.PerPage)
@@ -225,3 +271,38 @@ func (s *mysqlItemStore) ListItems(ctx context.Context, params pagination.ListPa
 
 	return items, total, nil
 }
+
+// ListItemsByCursor retrieves one page of the filtered items after or before params.Cursor.
+func (s *mysqlItemStore) ListItemsByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.ItemFilters],
+) ([]domain.Item, pagination.CursorPage, error) {
+	defer s.queryMetrics.observe("item", "ListItemsByCursor")()
+
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).Select(
+		"id", "name", "slug", "is_raw_material",
+		"description", "image_url", "version", "created_at", "updated_at",
+	).From("items").Where(itemFilters(params.Filters))
+
+	items, page, err := selectCursorPage(ctx, s.db, selectBuilder, itemSorts, params.Sort, params.PerPage, params.Cursor)
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error listing items: %w", err)
+	}
+	return items, page, nil
+}
+
+// GetItemListState counts the items matched by filters and finds their newest change.
+func (s *mysqlItemStore) GetItemListState(ctx context.Context, filters domain.ItemFilters) (pagination.ListState, error) {
+	defer s.queryMetrics.observe("item", "GetItemListState")()
+
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select().
+		From("items").
+		Where(itemFilters(filters))
+
+	state, err := selectListState(ctx, s.db, selectBuilder, listStateColumns{updatedAt: []string{"updated_at"}, versions: []string{"version"}})
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error summarizing items: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,231 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/jmoiron/sqlx"
+)
+
+// sortField is a column a list can be ordered by.
+type sortField[T any] struct {
+	column string
+	key    func(row T) any // The row's value of column, carried in cursors
+}
+
+// listSort describes how a list of T can be ordered. Ties are broken by ID in the
+// direction of the sort, which keeps pages stable and makes keyset pagination possible.
+type listSort[T any] struct {
+	fields       map[string]sortField[T]
+	defaultField string
+	defaultDesc  bool
+	idColumn     string
+	id           func(row T) uint64
+}
+
+// resolve returns the field and direction named by a sort parameter such as "name_asc",
+// falling back to the default sort for unknown fields.
+func (ls listSort[T]) resolve(sort string) (sortField[T], bool) {
+	if name, desc, ok := pagination.ParseSort(sort); ok {
+		if field, ok := ls.fields[name]; ok {
+			return field, desc
+		}
+	}
+	return ls.fields[ls.defaultField], ls.defaultDesc
+}
+
+// orderBy returns the ORDER BY clauses for a sort parameter.
+func (ls listSort[T]) orderBy(sort string) []string {
+	field, desc := ls.resolve(sort)
+	return ls.orderByDirection(field, desc)
+}
+
+func (ls listSort[T]) orderByDirection(field sortField[T], desc bool) []string {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	return []string{field.column + " " + direction, ls.idColumn + " " + direction}
+}
+
+// selectCursorPage runs the filtered select as one page of a keyset-paginated list.
+// Instead of an OFFSET it continues from the cursor's row, and it fetches one extra row
+// to tell whether another page follows rather than counting the matches.
+func selectCursorPage[T any](
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	selectBuilder sq.SelectBuilder,
+	ls listSort[T],
+	sort string, perPage int, cursor *pagination.Cursor,
+) ([]T, pagination.CursorPage, error) {
+	field, desc := ls.resolve(sort)
+	backward := cursor != nil && cursor.Backward
+
+	if cursor != nil {
+		key, err := decodeCursorKey(cursor.Key, field.key(*new(T)))
+		if err != nil {
+			return nil, pagination.CursorPage{}, err
+		}
+		op := ">"
+		if desc != backward {
+			op = "<"
+		}
+		selectBuilder = selectBuilder.Where(sq.Expr(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", field.column, op, field.column, ls.idColumn, op),
+			key, key, cursor.ID,
+		))
+	}
+
+	// Walking backwards reads the rows before the cursor in reverse and flips them afterwards
+	query, args, err := selectBuilder.
+		OrderBy(ls.orderByDirection(field, desc != backward)...).
+		Limit(uint64(perPage) + 1).
+		ToSql()
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error building cursor page query: %w", err)
+	}
+
+	rows := []T{}
+	if err := sqlx.SelectContext(ctx, db, &rows, query, args...); err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error executing cursor page query: %w", err)
+	}
+
+	hasMore := len(rows) > perPage
+	if hasMore {
+		rows = rows[:perPage]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	var page pagination.CursorPage
+	if len(rows) == 0 {
+		return rows, page, nil
+	}
+	cursorAt := func(row T, backward bool) (*string, error) {
+		key, err := json.Marshal(field.key(row))
+		if err != nil {
+			return nil, fmt.Errorf("error encoding cursor key: %w", err)
+		}
+		encoded := pagination.Cursor{Sort: sort, Key: key, ID: ls.id(row), Backward: backward}.Encode()
+		return &encoded, nil
+	}
+
+	// Going forward there are earlier rows whenever we started from a cursor, and the
+	// same holds for later rows when going backward
+	if hasMore || backward {
+		if page.Next, err = cursorAt(rows[len(rows)-1], false); err != nil {
+			return nil, pagination.CursorPage{}, err
+		}
+	}
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		if page.Prev, err = cursorAt(rows[0], true); err != nil {
+			return nil, pagination.CursorPage{}, err
+		}
+	}
+	return rows, page, nil
+}
+
+// countRows runs the COUNT(*) select of a paginated list, unless known already holds the total.
+func countRows(ctx context.Context, db sqlx.QueryerContext, countBuilder sq.SelectBuilder, known *int64) (int64, error) {
+	if known != nil {
+		return *known, nil
+	}
+
+	query, args, err := countBuilder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building count query: %w", err)
+	}
+	var total int64
+	if err := sqlx.GetContext(ctx, db, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("error executing count query: %w", err)
+	}
+	return total, nil
+}
+
+// listStateColumns names the columns a list's state is computed from. Rows joined in for
+// what the list embeds must not multiply the listed rows, or the count is off.
+type listStateColumns struct {
+	updatedAt []string // The newest value among these is the list's last modification
+	versions  []string // Summed into the revision; each must change with every write to its row
+}
+
+// selectListState counts the rows of a filtered select, finds the newest value among their
+// updatedAt columns and sums their versions. The select should name no columns; this adds
+// the aggregates.
+func selectListState(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	selectBuilder sq.SelectBuilder,
+	columns listStateColumns,
+) (pagination.ListState, error) {
+	selectBuilder = selectBuilder.Columns("COUNT(*)")
+	for _, column := range columns.updatedAt {
+		selectBuilder = selectBuilder.Columns("MAX(" + column + ")")
+	}
+	for _, column := range columns.versions {
+		selectBuilder = selectBuilder.Columns("COALESCE(SUM(" + column + "), 0)")
+	}
+	query, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error building list state query: %w", err)
+	}
+
+	var state pagination.ListState
+	lastModified := make([]sql.NullTime, len(columns.updatedAt))
+	versions := make([]uint64, len(columns.versions))
+	dest := []any{&state.Count}
+	for i := range lastModified {
+		dest = append(dest, &lastModified[i])
+	}
+	for i := range versions {
+		dest = append(dest, &versions[i])
+	}
+	if err := db.QueryRowxContext(ctx, query, args...).Scan(dest...); err != nil {
+		return pagination.ListState{}, fmt.Errorf("error executing list state query: %w", err)
+	}
+
+	for _, t := range lastModified {
+		if t.Valid && t.Time.After(state.LastModified) {
+			state.LastModified = t.Time
+		}
+	}
+	for _, v := range versions {
+		state.Revision += v
+	}
+	return state, nil
+}
+
+// decodeCursorKey decodes a cursor's sort key into the type of the sort column's values.
+func decodeCursorKey(raw json.RawMessage, like any) (any, error) {
+	var (
+		key any
+		err error
+	)
+	switch like.(type) {
+	case string:
+		var v string
+		err = json.Unmarshal(raw, &v)
+		key = v
+	case uint64:
+		var v uint64
+		err = json.Unmarshal(raw, &v)
+		key = v
+	case time.Time:
+		var v time.Time
+		err = json.Unmarshal(raw, &v)
+		key = v
+	default:
+		return nil, fmt.Errorf("unsupported cursor key type %T", like)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor does not match the sort: %v", pagination.ErrInvalidCursor, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+)
+
+func TestDecodeCursorKey(t *testing.T) {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		raw     string
+		like    any
+		want    any
+		wantErr error // nil for success
+	}{
+		{"string", `"Iron Plate"`, "", "Iron Plate", nil},
+		{"empty string", `""`, "", "", nil},
+		{"uint64", `42`, uint64(0), uint64(42), nil},
+		{"largest uint64", `18446744073709551615`, uint64(0), uint64(18446744073709551615), nil},
+		{"time", `"2025-01-01T12:00:00Z"`, time.Time{}, created, nil},
+		{"number for a string", `42`, "", nil, pagination.ErrInvalidCursor},
+		{"string for a number", `"42"`, uint64(0), nil, pagination.ErrInvalidCursor},
+		{"negative number", `-1`, uint64(0), nil, pagination.ErrInvalidCursor},
+		{"fraction", `1.5`, uint64(0), nil, pagination.ErrInvalidCursor},
+		{"not a time", `"yesterday"`, time.Time{}, nil, pagination.ErrInvalidCursor},
+		{"object", `{"a":1}`, "", nil, pagination.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursorKey(json.RawMessage(tt.raw), tt.like)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("decodeCursorKey(%s) = %v, %v, want error %v", tt.raw, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursorKey(%s) error = %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCursorKey(%s) = %#v, want %#v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorKeyUnsupportedType(t *testing.T) {
+	// A sort field with a key type decodeCursorKey does not know is a programming error,
+	// not a bad cursor
+	_, err := decodeCursorKey(json.RawMessage(`1`), 1.5)
+	if err == nil || errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("decodeCursorKey() error = %v, want an unsupported type error", err)
+	}
+}
+
+func TestListSortOrderBy(t *testing.T) {
+	type row struct {
+		ID   uint64
+		Name string
+	}
+	ls := listSort[row]{
+		fields: map[string]sortField[row]{
+			"name": {column: "r.name", key: func(r row) any { return r.Name }},
+			"id":   {column: "r.id", key: func(r row) any { return r.ID }},
+		},
+		defaultField: "id",
+		defaultDesc:  true,
+		idColumn:     "r.id",
+		id:           func(r row) uint64 { return r.ID },
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"name_asc", []string{"r.name ASC", "r.id ASC"}},
+		{"name_desc", []string{"r.name DESC", "r.id DESC"}},
+		{"id_asc", []string{"r.id ASC", "r.id ASC"}},
+		{"", []string{"r.id DESC", "r.id DESC"}},
+		{"name", []string{"r.id DESC", "r.id DESC"}},
+		{"password_asc", []string{"r.id DESC", "r.id DESC"}},
+		{"r.name; DROP TABLE items_asc", []string{"r.id DESC", "r.id DESC"}},
+	}
+	for _, tt := range tests {
+		if got := ls.orderBy(tt.sort); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("orderBy(%q) = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/metrics"
+)
+
+// QueryMetrics records how long store methods take, labeled by store and method.
+type QueryMetrics struct {
+	duration *metrics.HistogramVec
+}
+
+// NewQueryMetrics registers the store query duration histogram.
+func NewQueryMetrics(registry *metrics.Registry) *QueryMetrics {
+	return &QueryMetrics{
+		duration: registry.NewHistogramVec(
+			"db_store_query_duration_seconds",
+			"Duration of store methods, including every query they run.",
+			metrics.DefaultBuckets,
+			"store", "method",
+		),
+	}
+}
+
+// observe starts timing a store method and returns the func that records it, to be
+// deferred. A nil QueryMetrics records nothing.
+func (m *QueryMetrics) observe(store, method string) func() {
+	if m == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		m.duration.Observe(time.Since(start).Seconds(), store, method)
+	}
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlPreferredRecipeStore implements PreferredRecipeStore interface
+var _ storage.PreferredRecipeStore = (*mysqlPreferredRecipeStore)(nil)
+
+type mysqlPreferredRecipeStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLPreferredRecipeStore(db *sqlx.DB) *mysqlPreferredRecipeStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlPreferredRecipeStore{db: db}
+}
+
+// SetPreferredRecipe upserts the preference on the (user_id, output_item_id) unique key.
+func (s *mysqlPreferredRecipeStore) SetPreferredRecipe(ctx context.Context, pref *domain.UserPreferredRecipe) error {
+	now := time.Now()
+	pref.CreatedAt = now
+	pref.UpdatedAt = now
+
+	query := `
+		INSERT INTO user_preferred_recipes (user_id, output_item_id, preferred_recipe_id, created_at, updated_at)
+		VALUES (:user_id, :output_item_id, :preferred_recipe_id, :created_at, :updated_at)
+		ON DUPLICATE KEY UPDATE
+			preferred_recipe_id = VALUES(preferred_recipe_id),
+			updated_at = VALUES(updated_at)
+	`
+	_, err := s.db.NamedExecContext(ctx, query, pref)
+	if err != nil {
+		if isMySQLError(err, mysqlErrNoReferencedRow) {
+			return fmt.Errorf("setting preferred recipe failed: %w: %s", storage.ErrInvalidReference, err.Error())
+		}
+		return fmt.Errorf("error setting preferred recipe for user %d and item %d: %w", pref.UserID, pref.OutputItemID, err)
+	}
+
+	return nil
+}
+
+// GetPreferredRecipe retrieves the user's preference for an output item.
+func (s *mysqlPreferredRecipeStore) GetPreferredRecipe(ctx context.Context, userID, outputItemID uint64) (*domain.UserPreferredRecipe, error) {
+	query := `
+		SELECT id, user_id, output_item_id, preferred_recipe_id, created_at, updated_at
+		FROM user_preferred_recipes
+		WHERE user_id = ? AND output_item_id = ?
+	`
+	var pref domain.UserPreferredRecipe
+
+	err := s.db.GetContext(ctx, &pref, query, userID, outputItemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching preferred recipe for user %d and item %d: %w", userID, outputItemID, err)
+	}
+	return &pref, nil
+}
+
+// DeletePreferredRecipe removes the user's preference for an output item.
+func (s *mysqlPreferredRecipeStore) DeletePreferredRecipe(ctx context.Context, userID, outputItemID uint64) error {
+	query := "DELETE FROM user_preferred_recipes WHERE user_id = ? AND output_item_id = ?"
+	res, err := s.db.ExecContext(ctx, query, userID, outputItemID)
+	if err != nil {
+		return fmt.Errorf("error deleting preferred recipe for user %d and item %d: %w", userID, outputItemID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting preferred recipe: %w", err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListPreferredRecipes retrieves all preferences of a user ordered by output item.
+func (s *mysqlPreferredRecipeStore) ListPreferredRecipes(ctx context.Context, userID uint64) ([]domain.UserPreferredRecipe, error) {
+	query := `
+		SELECT id, user_id, output_item_id, preferred_recipe_id, created_at, updated_at
+		FROM user_preferred_recipes
+		WHERE user_id = ?
+		ORDER BY output_item_id
+	`
+	prefs := []domain.UserPreferredRecipe{}
+	if err := s.db.SelectContext(ctx, &prefs, query, userID); err != nil {
+		return nil, fmt.Errorf("error listing preferred recipes for user %d: %w", userID, err)
+	}
+	return prefs, nil
+}
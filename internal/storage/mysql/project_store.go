@@ -0,0 +1,343 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlProjectStore implements ProjectStore interface
+var _ storage.ProjectStore = (*mysqlProjectStore)(nil)
+
+type mysqlProjectStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLProjectStore(db *sqlx.DB) *mysqlProjectStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlProjectStore{db: db}
+}
+
+const projectColumns = "id, user_id, name, description, created_at, updated_at"
+
+// projectItemColumns selects a project item joined with its item as "pi" and "i".
+// The remaining quantity is computed without subtracting below zero, which unsigned columns reject.
+const projectItemColumns = `pi.id, pi.project_id, pi.item_id, i.name AS item_name, i.slug AS item_slug,
+	pi.target_quantity, pi.produced_quantity,
+	IF(pi.produced_quantity >= pi.target_quantity, 0, pi.target_quantity - pi.produced_quantity) AS remaining_quantity,
+	pi.created_at, pi.updated_at`
+
+// CreateProject inserts a project and all of its items in a single transaction.
+func (s *mysqlProjectStore) CreateProject(ctx context.Context, project *domain.Project) error {
+	now := time.Now()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for project creation: %w", err)
+	}
+	defer tx.Rollback() // No-op once committed
+
+	query := `
+		INSERT INTO projects (user_id, name, description, created_at, updated_at)
+		VALUES (:user_id, :name, :description, :created_at, :updated_at);
+	`
+	res, err := tx.NamedExecContext(ctx, query, project)
+	if err != nil {
+		return wrapWriteError("project creation failed", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID after creating project: %w", err)
+	}
+	project.ID = uint64(id)
+
+	for i := range project.Items {
+		item := &project.Items[i]
+		item.ProjectID = project.ID
+		if err := upsertProjectItem(ctx, tx, item); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing project creation: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectByID retrieves a single project, including its items.
+func (s *mysqlProjectStore) GetProjectByID(ctx context.Context, id uint64) (*domain.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects WHERE id = ?"
+	var project domain.Project
+
+	err := s.db.GetContext(ctx, &project, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching project with id %d: %w", id, err)
+	}
+
+	projects := []domain.Project{project}
+	if err := loadProjectItems(ctx, s.db, projects); err != nil {
+		return nil, err
+	}
+
+	return &projects[0], nil
+}
+
+// UpdateProject updates the name and description of a project.
+func (s *mysqlProjectStore) UpdateProject(ctx context.Context, project *domain.Project) error {
+	project.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE projects SET
+			name = :name,
+			description = :description,
+			version = version + 1,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	res, err := s.db.NamedExecContext(ctx, query, project)
+	if err != nil {
+		return wrapWriteError(fmt.Sprintf("project %d update failed", project.ID), err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after updating project %d: %w", project.ID, err)
+	}
+	if rowsAffected == 0 {
+		// The version always changes, so only a missing project matches no row
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteProject deletes a project. Its items are removed by ON DELETE CASCADE.
+func (s *mysqlProjectStore) DeleteProject(ctx context.Context, id uint64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM projects WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting project with id %d: %w", id, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting project %d: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// projectSorts lists the fields projects can be sorted by, e.g. "name_asc" or "updated_at_desc".
+var projectSorts = listSort[domain.Project]{
+	fields: map[string]sortField[domain.Project]{
+		"name":       {column: "name", key: func(p domain.Project) any { return p.Name }},
+		"created_at": {column: "created_at", key: func(p domain.Project) any { return p.CreatedAt }},
+		"updated_at": {column: "updated_at", key: func(p domain.Project) any { return p.UpdatedAt }},
+	},
+	defaultField: "updated_at",
+	defaultDesc:  true,
+	idColumn:     "id",
+	id:           func(p domain.Project) uint64 { return p.ID },
+}
+
+// projectFilters returns the conditions selecting the projects matched by filters.
+func projectFilters(filters domain.ProjectFilters) sq.And {
+	conditions := sq.And{sq.Eq{"user_id": filters.UserID}}
+	if filters.Name != nil && *filters.Name != "" {
+		conditions = append(conditions, sq.Like{"name": "%" + *filters.Name + "%"})
+	}
+	return conditions
+}
+
+// ListProjects retrieves a paginated and filtered list of a user's projects with their items.
+func (s *mysqlProjectStore) ListProjects(ctx context.Context, params pagination.ListParams[domain.ProjectFilters]) ([]domain.Project, int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	filters := projectFilters(params.Filters)
+
+	selectBuilder := psql.Select(strings.Split(projectColumns, ", ")...).
+		From("projects").
+		Where(filters)
+	countBuilder := psql.Select("COUNT(*)").
+		From("projects").
+		Where(filters)
+
+	total, err := countRows(ctx, s.db, countBuilder, params.Total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting projects: %w", err)
+	}
+
+	if total == 0 {
+		return []domain.Project{}, 0, nil
+	}
+
+	selectBuilder = selectBuilder.OrderBy(projectSorts.orderBy(params.Sort)...)
+
+	offset := uint64((params.Page - 1) * params.PerPage)
+	selectBuilder = selectBuilder.Limit(uint64(params.PerPage)).Offset(offset)
+
+	projectsQuery, projectsArgs, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error building select query for projects: %w", err)
+	}
+
+	projects := []domain.Project{}
+	err = s.db.SelectContext(ctx, &projects, projectsQuery, projectsArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error executing select query for projects: %w", err)
+	}
+
+	if err := loadProjectItems(ctx, s.db, projects); err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, nil
+}
+
+// ListProjectsByCursor retrieves one page of a user's projects after or before params.Cursor.
+func (s *mysqlProjectStore) ListProjectsByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.ProjectFilters],
+) ([]domain.Project, pagination.CursorPage, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select(strings.Split(projectColumns, ", ")...).
+		From("projects").
+		Where(projectFilters(params.Filters))
+
+	projects, page, err := selectCursorPage(ctx, s.db, selectBuilder, projectSorts, params.Sort, params.PerPage, params.Cursor)
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error listing projects: %w", err)
+	}
+
+	if err := loadProjectItems(ctx, s.db, projects); err != nil {
+		return nil, pagination.CursorPage{}, err
+	}
+
+	return projects, page, nil
+}
+
+// GetProjectListState counts the projects matched by filters and finds their newest change.
+// Changes to their items, including the names of the items, count as changes of the projects.
+func (s *mysqlProjectStore) GetProjectListState(ctx context.Context, filters domain.ProjectFilters) (pagination.ListState, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select().
+		From("projects").
+		// Summarized per project, so the items don't multiply the projects being counted
+		LeftJoin(`(
+			SELECT pi.project_id,
+				MAX(GREATEST(pi.updated_at, i.updated_at)) AS items_updated_at,
+				SUM(pi.version + i.version) AS items_version
+			FROM project_items pi
+			JOIN items i ON i.id = pi.item_id
+			GROUP BY pi.project_id
+		) project_item_states ON project_item_states.project_id = projects.id`).
+		Where(projectFilters(filters))
+
+	state, err := selectListState(ctx, s.db, selectBuilder, listStateColumns{
+		updatedAt: []string{"projects.updated_at", "project_item_states.items_updated_at"},
+		versions:  []string{"projects.version", "project_item_states.items_version"},
+	})
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error summarizing projects: %w", err)
+	}
+	return state, nil
+}
+
+// SetProjectItem upserts the item on the (project_id, item_id) unique key.
+func (s *mysqlProjectStore) SetProjectItem(ctx context.Context, item *domain.ProjectItem) error {
+	return upsertProjectItem(ctx, s.db, item)
+}
+
+// DeleteProjectItem removes an item from a project.
+func (s *mysqlProjectStore) DeleteProjectItem(ctx context.Context, projectID, itemID uint64) error {
+	query := "DELETE FROM project_items WHERE project_id = ? AND item_id = ?"
+	res, err := s.db.ExecContext(ctx, query, projectID, itemID)
+	if err != nil {
+		return fmt.Errorf("error deleting item %d of project %d: %w", itemID, projectID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting project item: %w", err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+func upsertProjectItem(ctx context.Context, db sqlx.ExtContext, item *domain.ProjectItem) error {
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	query := `
+		INSERT INTO project_items (project_id, item_id, target_quantity, produced_quantity, created_at, updated_at)
+		VALUES (:project_id, :item_id, :target_quantity, :produced_quantity, :created_at, :updated_at)
+		ON DUPLICATE KEY UPDATE
+			target_quantity = VALUES(target_quantity),
+			produced_quantity = VALUES(produced_quantity),
+			version = version + 1,
+			updated_at = VALUES(updated_at)
+	`
+	if _, err := sqlx.NamedExecContext(ctx, db, query, item); err != nil {
+		return wrapWriteError(fmt.Sprintf("setting item %d of project %d failed", item.ItemID, item.ProjectID), err)
+	}
+	return nil
+}
+
+// loadProjectItems fetches the items for the given projects with one query and attaches them in place.
+func loadProjectItems(ctx context.Context, db sqlx.QueryerContext, projects []domain.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(projects))
+	byID := make(map[uint64]*domain.Project, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+		projects[i].Items = []domain.ProjectItem{}
+		byID[projects[i].ID] = &projects[i]
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT `+projectItemColumns+`
+		FROM project_items pi
+		JOIN items i ON i.id = pi.item_id
+		WHERE pi.project_id IN (?)
+		ORDER BY pi.id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("error building project items query: %w", err)
+	}
+	var items []domain.ProjectItem
+	if err := sqlx.SelectContext(ctx, db, &items, query, args...); err != nil {
+		return fmt.Errorf("error fetching project items: %w", err)
+	}
+	for _, item := range items {
+		p := byID[item.ProjectID]
+		p.Items = append(p.Items, item)
+	}
+
+	return nil
+}
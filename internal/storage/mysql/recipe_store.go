@@ -0,0 +1,471 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlRecipeStore implements RecipeStore interface
+var _ storage.RecipeStore = (*mysqlRecipeStore)(nil)
+
+type mysqlRecipeStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLRecipeStore(db *sqlx.DB) *mysqlRecipeStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlRecipeStore{db: db}
+}
+
+const recipeColumns = "id, name, crafting_method_id, eu_per_tick, duration_ticks, notes, is_default, created_at, updated_at"
+
+// CreateRecipe inserts a recipe and all of its inputs and outputs in a single transaction.
+func (s *mysqlRecipeStore) CreateRecipe(ctx context.Context, recipe *domain.Recipe) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for recipe creation: %w", err)
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if err := insertRecipe(ctx, tx, recipe); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing recipe creation: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecipeByID retrieves a single recipe, including its inputs and outputs.
+func (s *mysqlRecipeStore) GetRecipeByID(ctx context.Context, id uint64) (*domain.Recipe, error) {
+	query := "SELECT " + recipeColumns + " FROM recipes WHERE id = ?"
+	var recipe domain.Recipe
+
+	err := s.db.GetContext(ctx, &recipe, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching recipe with id %d: %w", id, err)
+	}
+
+	recipes := []domain.Recipe{recipe}
+	if err := loadRecipeParts(ctx, s.db, recipes); err != nil {
+		return nil, err
+	}
+
+	return &recipes[0], nil
+}
+
+// UpdateRecipe updates a recipe and replaces its inputs and outputs in a single transaction.
+func (s *mysqlRecipeStore) UpdateRecipe(ctx context.Context, recipe *domain.Recipe) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for recipe %d update: %w", recipe.ID, err)
+	}
+	defer tx.Rollback()
+
+	if err := updateRecipe(ctx, tx, recipe); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing recipe %d update: %w", recipe.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteRecipe deletes a recipe. Inputs and outputs are removed by ON DELETE CASCADE.
+func (s *mysqlRecipeStore) DeleteRecipe(ctx context.Context, id uint64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM recipes WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting recipe with id %d: %w", id, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected after deleting recipe %d: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// recipeSorts lists the fields recipes can be sorted by, e.g. "name_asc" or "created_at_desc".
+// Unnamed recipes sort as if their name was empty.
+var recipeSorts = listSort[domain.Recipe]{
+	fields: map[string]sortField[domain.Recipe]{
+		"name":       {column: "COALESCE(name, '')", key: func(r domain.Recipe) any { return r.Name.String }},
+		"created_at": {column: "created_at", key: func(r domain.Recipe) any { return r.CreatedAt }},
+		"updated_at": {column: "updated_at", key: func(r domain.Recipe) any { return r.UpdatedAt }},
+	},
+	defaultField: "created_at",
+	defaultDesc:  true,
+	idColumn:     "id",
+	id:           func(r domain.Recipe) uint64 { return r.ID },
+}
+
+// recipeFilters returns the conditions selecting the recipes matched by filters.
+func recipeFilters(filters domain.RecipeFilters) sq.And {
+	conditions := sq.And{}
+	if filters.Name != nil && *filters.Name != "" {
+		conditions = append(conditions, sq.Like{"name": "%" + *filters.Name + "%"})
+	}
+	if filters.CraftingMethodID != nil {
+		conditions = append(conditions, sq.Eq{"crafting_method_id": *filters.CraftingMethodID})
+	}
+	if filters.IsDefault != nil {
+		conditions = append(conditions, sq.Eq{"is_default": *filters.IsDefault})
+	}
+	if filters.InputItemID != nil {
+		conditions = append(conditions, sq.Expr("id IN (SELECT recipe_id FROM recipe_inputs WHERE input_item_id = ?)", *filters.InputItemID))
+	}
+	if filters.OutputItemID != nil {
+		conditions = append(conditions, sq.Expr("id IN (SELECT recipe_id FROM recipe_outputs WHERE item_id = ?)", *filters.OutputItemID))
+	}
+	return conditions
+}
+
+// ListRecipes retrieves a paginated and filtered list of recipes with their inputs and outputs.
+func (s *mysqlRecipeStore) ListRecipes(ctx context.Context, params pagination.ListParams[domain.RecipeFilters]) ([]domain.Recipe, int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Question)
+	filters := recipeFilters(params.Filters)
+
+	selectBuilder := psql.Select(strings.Split(recipeColumns, ", ")...).From("recipes").Where(filters)
+	countBuilder := psql.Select("COUNT(*)").From("recipes").Where(filters)
+
+	total, err := countRows(ctx, s.db, countBuilder, params.Total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting recipes: %w", err)
+	}
+
+	if total == 0 {
+		return []domain.Recipe{}, 0, nil
+	}
+
+	selectBuilder = selectBuilder.OrderBy(recipeSorts.orderBy(params.Sort)...)
+
+	offset := uint64((params.Page - 1) * params.PerPage)
+	selectBuilder = selectBuilder.Limit(uint64(params.PerPage)).Offset(offset)
+
+	recipesQuery, recipesArgs, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error building select query for recipes: %w", err)
+	}
+
+	recipes := []domain.Recipe{}
+	err = s.db.SelectContext(ctx, &recipes, recipesQuery, recipesArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error executing select query for recipes: %w", err)
+	}
+
+	if err := loadRecipeParts(ctx, s.db, recipes); err != nil {
+		return nil, 0, err
+	}
+
+	return recipes, total, nil
+}
+
+// ListRecipesByCursor retrieves one page of the filtered recipes after or before params.Cursor.
+func (s *mysqlRecipeStore) ListRecipesByCursor(
+	ctx context.Context,
+	params pagination.ListParams[domain.RecipeFilters],
+) ([]domain.Recipe, pagination.CursorPage, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select(strings.Split(recipeColumns, ", ")...).
+		From("recipes").
+		Where(recipeFilters(params.Filters))
+
+	recipes, page, err := selectCursorPage(ctx, s.db, selectBuilder, recipeSorts, params.Sort, params.PerPage, params.Cursor)
+	if err != nil {
+		return nil, pagination.CursorPage{}, fmt.Errorf("error listing recipes: %w", err)
+	}
+
+	if err := loadRecipeParts(ctx, s.db, recipes); err != nil {
+		return nil, pagination.CursorPage{}, err
+	}
+
+	return recipes, page, nil
+}
+
+// GetRecipeListState counts the recipes matched by filters and finds their newest change.
+// Updates rewrite the inputs and outputs along with the recipe, but deleting an item removes
+// them on its own, so the number of inputs and outputs is part of the state.
+func (s *mysqlRecipeStore) GetRecipeListState(ctx context.Context, filters domain.RecipeFilters) (pagination.ListState, error) {
+	selectBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Question).
+		Select().
+		From("recipes").
+		// Counted per recipe, so the parts don't multiply the recipes being counted
+		LeftJoin(`(
+			SELECT recipe_id, COUNT(*) AS part_count
+			FROM (
+				SELECT recipe_id FROM recipe_inputs
+				UNION ALL
+				SELECT recipe_id FROM recipe_outputs
+			) recipe_parts
+			GROUP BY recipe_id
+		) recipe_part_counts ON recipe_part_counts.recipe_id = recipes.id`).
+		Where(recipeFilters(filters))
+
+	state, err := selectListState(ctx, s.db, selectBuilder, listStateColumns{
+		updatedAt: []string{"recipes.updated_at"},
+		versions:  []string{"recipes.version", "recipe_part_counts.part_count"},
+	})
+	if err != nil {
+		return pagination.ListState{}, fmt.Errorf("error summarizing recipes: %w", err)
+	}
+	return state, nil
+}
+
+// ListRecipesByOutputItem retrieves all recipes that list the item among their outputs.
+func (s *mysqlRecipeStore) ListRecipesByOutputItem(ctx context.Context, itemID uint64) ([]domain.Recipe, error) {
+	query := `
+		SELECT r.id, r.name, r.crafting_method_id, r.eu_per_tick, r.duration_ticks, r.notes, r.is_default, r.created_at, r.updated_at
+		FROM recipes r
+		JOIN recipe_outputs ro ON ro.recipe_id = r.id
+		WHERE ro.item_id = ?
+		ORDER BY r.is_default DESC, ro.is_primary_output DESC, r.id ASC
+	`
+	recipes := []domain.Recipe{}
+	if err := s.db.SelectContext(ctx, &recipes, query, itemID); err != nil {
+		return nil, fmt.Errorf("error fetching recipes producing item %d: %w", itemID, err)
+	}
+
+	if err := loadRecipeParts(ctx, s.db, recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// ListRecipesCraftableFrom retrieves the recipes whose input set is contained in the given items.
+func (s *mysqlRecipeStore) ListRecipesCraftableFrom(ctx context.Context, itemIDs []uint64) ([]domain.Recipe, error) {
+	if len(itemIDs) == 0 {
+		return []domain.Recipe{}, nil
+	}
+
+	// A recipe qualifies when it has inputs and none of them is missing from the set
+	query, args, err := sqlx.In(`
+		SELECT r.id, r.name, r.crafting_method_id, r.eu_per_tick, r.duration_ticks, r.notes, r.is_default, r.created_at, r.updated_at
+		FROM recipes r
+		WHERE EXISTS (SELECT 1 FROM recipe_inputs ri WHERE ri.recipe_id = r.id)
+		  AND NOT EXISTS (
+			SELECT 1 FROM recipe_inputs ri
+			WHERE ri.recipe_id = r.id AND ri.input_item_id NOT IN (?)
+		  )
+		ORDER BY r.id ASC
+	`, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building craftable recipes query: %w", err)
+	}
+
+	recipes := []domain.Recipe{}
+	if err := s.db.SelectContext(ctx, &recipes, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetching recipes craftable from %d items: %w", len(itemIDs), err)
+	}
+
+	if err := loadRecipeParts(ctx, s.db, recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// ListRecipeEdges retrieves the item dependency edges of all recipes.
+func (s *mysqlRecipeStore) ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error) {
+	return listRecipeEdges(ctx, s.db)
+}
+
+// insertRecipe writes a new recipe with its inputs and outputs using the given transaction.
+func insertRecipe(ctx context.Context, tx *sqlx.Tx, recipe *domain.Recipe) error {
+	now := time.Now()
+	recipe.CreatedAt = now
+	recipe.UpdatedAt = now
+
+	query := `
+		INSERT INTO recipes (name, crafting_method_id, eu_per_tick, duration_ticks, notes, is_default, created_at, updated_at)
+		VALUES (:name, :crafting_method_id, :eu_per_tick, :duration_ticks, :notes, :is_default, :created_at, :updated_at);
+	`
+	res, err := tx.NamedExecContext(ctx, query, recipe)
+	if err != nil {
+		return wrapWriteError("recipe creation failed", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID after creating recipe: %w", err)
+	}
+	recipe.ID = uint64(id)
+
+	return insertRecipeParts(ctx, tx, recipe)
+}
+
+// updateRecipe updates a recipe and replaces its inputs and outputs using the given transaction.
+func updateRecipe(ctx context.Context, tx *sqlx.Tx, recipe *domain.Recipe) error {
+	recipe.UpdatedAt = time.Now()
+
+	// Lock the row first: MySQL reports zero affected rows for an UPDATE that
+	// changes nothing, so RowsAffected can't be used to detect a missing recipe.
+	var locked int
+	err := tx.GetContext(ctx, &locked, "SELECT 1 FROM recipes WHERE id = ? FOR UPDATE", recipe.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrNotFound
+		}
+		return fmt.Errorf("error locking recipe %d for update: %w", recipe.ID, err)
+	}
+
+	query := `
+		UPDATE recipes SET
+			name = :name,
+			crafting_method_id = :crafting_method_id,
+			eu_per_tick = :eu_per_tick,
+			duration_ticks = :duration_ticks,
+			notes = :notes,
+			is_default = :is_default,
+			version = version + 1,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	if _, err := tx.NamedExecContext(ctx, query, recipe); err != nil {
+		return wrapWriteError(fmt.Sprintf("recipe %d update failed", recipe.ID), err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_inputs WHERE recipe_id = ?", recipe.ID); err != nil {
+		return fmt.Errorf("error clearing inputs of recipe %d: %w", recipe.ID, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_outputs WHERE recipe_id = ?", recipe.ID); err != nil {
+		return fmt.Errorf("error clearing outputs of recipe %d: %w", recipe.ID, err)
+	}
+
+	return insertRecipeParts(ctx, tx, recipe)
+}
+
+// listRecipeEdges fetches one edge per (output, input) pair of every recipe.
+func listRecipeEdges(ctx context.Context, db sqlx.QueryerContext) ([]domain.RecipeEdge, error) {
+	query := `
+		SELECT ro.recipe_id, ro.item_id AS output_item_id, ri.input_item_id
+		FROM recipe_outputs ro
+		JOIN recipe_inputs ri ON ri.recipe_id = ro.recipe_id
+		ORDER BY ro.recipe_id, ro.item_id, ri.input_item_id
+	`
+	edges := []domain.RecipeEdge{}
+	if err := sqlx.SelectContext(ctx, db, &edges, query); err != nil {
+		return nil, fmt.Errorf("error fetching recipe edges: %w", err)
+	}
+	return edges, nil
+}
+
+// insertRecipeParts writes the inputs and outputs of a recipe using the given transaction.
+// The generated IDs are written back into the recipe.
+func insertRecipeParts(ctx context.Context, tx *sqlx.Tx, recipe *domain.Recipe) error {
+	for i := range recipe.Inputs {
+		input := &recipe.Inputs[i]
+		input.RecipeID = recipe.ID
+
+		res, err := tx.NamedExecContext(ctx, `
+			INSERT INTO recipe_inputs (recipe_id, input_item_id, input_quantity)
+			VALUES (:recipe_id, :input_item_id, :input_quantity)
+		`, input)
+		if err != nil {
+			return wrapWriteError(fmt.Sprintf("inserting input item %d of recipe %d failed", input.InputItemID, recipe.ID), err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID after creating recipe input: %w", err)
+		}
+		input.ID = uint64(id)
+	}
+
+	for i := range recipe.Outputs {
+		output := &recipe.Outputs[i]
+		output.RecipeID = recipe.ID
+
+		res, err := tx.NamedExecContext(ctx, `
+			INSERT INTO recipe_outputs (recipe_id, item_id, quantity, chance, is_primary_output)
+			VALUES (:recipe_id, :item_id, :quantity, :chance, :is_primary_output)
+		`, output)
+		if err != nil {
+			return wrapWriteError(fmt.Sprintf("inserting output item %d of recipe %d failed", output.ItemID, recipe.ID), err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error getting last insert ID after creating recipe output: %w", err)
+		}
+		output.ID = uint64(id)
+	}
+
+	return nil
+}
+
+// loadRecipeParts fetches the inputs and outputs for the given recipes with one query each
+// and attaches them in place.
+func loadRecipeParts(ctx context.Context, db sqlx.QueryerContext, recipes []domain.Recipe) error {
+	if len(recipes) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(recipes))
+	byID := make(map[uint64]*domain.Recipe, len(recipes))
+	for i := range recipes {
+		ids[i] = recipes[i].ID
+		recipes[i].Inputs = []domain.RecipeInput{}
+		recipes[i].Outputs = []domain.RecipeOutput{}
+		byID[recipes[i].ID] = &recipes[i]
+	}
+
+	inputsQuery, inputsArgs, err := sqlx.In(`
+		SELECT id, recipe_id, input_item_id, input_quantity
+		FROM recipe_inputs
+		WHERE recipe_id IN (?)
+		ORDER BY id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("error building recipe inputs query: %w", err)
+	}
+	var inputs []domain.RecipeInput
+	if err := sqlx.SelectContext(ctx, db, &inputs, inputsQuery, inputsArgs...); err != nil {
+		return fmt.Errorf("error fetching recipe inputs: %w", err)
+	}
+	for _, input := range inputs {
+		r := byID[input.RecipeID]
+		r.Inputs = append(r.Inputs, input)
+	}
+
+	outputsQuery, outputsArgs, err := sqlx.In(`
+		SELECT id, recipe_id, item_id, quantity, chance, is_primary_output
+		FROM recipe_outputs
+		WHERE recipe_id IN (?)
+		ORDER BY id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("error building recipe outputs query: %w", err)
+	}
+	var outputs []domain.RecipeOutput
+	if err := sqlx.SelectContext(ctx, db, &outputs, outputsQuery, outputsArgs...); err != nil {
+		return fmt.Errorf("error fetching recipe outputs: %w", err)
+	}
+	for _, output := range outputs {
+		r := byID[output.RecipeID]
+		r.Outputs = append(r.Outputs, output)
+	}
+
+	return nil
+}
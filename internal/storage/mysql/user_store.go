@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+	"github.com/dubbie/calculator-api/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ensure mysqlUserStore implements UserStore interface
+var _ storage.UserStore = (*mysqlUserStore)(nil)
+
+type mysqlUserStore struct {
+	db *sqlx.DB
+}
+
+func NewMySQLUserStore(db *sqlx.DB) *mysqlUserStore {
+	if db == nil {
+		panic("sqlx.DB instance is required")
+	}
+	return &mysqlUserStore{db: db}
+}
+
+// CreateUser inserts a new user. The password must already be hashed.
+func (s *mysqlUserStore) CreateUser(ctx context.Context, user *domain.User) error {
+	now := time.Now()
+	user.CreatedAt = domain.JSONNullTime{NullTime: sql.NullTime{Time: now, Valid: true}}
+	user.UpdatedAt = user.CreatedAt
+
+	query := `
+		INSERT INTO users (name, email, password, created_at, updated_at)
+		VALUES (:name, :email, :password, :created_at, :updated_at);
+	`
+
+	res, err := s.db.NamedExecContext(ctx, query, user)
+	if err != nil {
+		if isMySQLError(err, mysqlErrDuplicateEntry) {
+			return fmt.Errorf("user creation failed: %w: %s", storage.ErrDuplicateEntry, err.Error())
+		}
+		return fmt.Errorf("error creating user: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert ID after creating user: %w", err)
+	}
+	user.ID = uint64(id)
+
+	return nil
+}
+
+// GetUserByID retrieves a single user by ID.
+func (s *mysqlUserStore) GetUserByID(ctx context.Context, id uint64) (*domain.User, error) {
+	query := "SELECT id, name, email, password, remember_token, created_at, updated_at FROM users WHERE id = ?"
+	var user domain.User
+
+	err := s.db.GetContext(ctx, &user, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching user with id %d: %w", id, err)
+	}
+	return &user, nil
+}
+
+// GetUserByEmail retrieves a single user by email address.
+func (s *mysqlUserStore) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	query := "SELECT id, name, email, password, remember_token, created_at, updated_at FROM users WHERE email = ?"
+	var user domain.User
+
+	err := s.db.GetContext(ctx, &user, query, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching user by email: %w", err)
+	}
+	return &user, nil
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// PreferredRecipeStore defines the interface for storing users' preferred recipes.
+type PreferredRecipeStore interface {
+	// SetPreferredRecipe creates or replaces the preference for the user and output item.
+	SetPreferredRecipe(ctx context.Context, pref *domain.UserPreferredRecipe) error
+	GetPreferredRecipe(ctx context.Context, userID, outputItemID uint64) (*domain.UserPreferredRecipe, error)
+	DeletePreferredRecipe(ctx context.Context, userID, outputItemID uint64) error
+	ListPreferredRecipes(ctx context.Context, userID uint64) ([]domain.UserPreferredRecipe, error)
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// ProjectStore defines the interface for project storage operations.
+type ProjectStore interface {
+	// CreateProject inserts the project together with its items.
+	CreateProject(ctx context.Context, project *domain.Project) error
+	GetProjectByID(ctx context.Context, id uint64) (*domain.Project, error)
+	// UpdateProject updates the project's own fields; its items are left untouched.
+	UpdateProject(ctx context.Context, project *domain.Project) error
+	DeleteProject(ctx context.Context, id uint64) error
+	ListProjects(ctx context.Context, params pagination.ListParams[domain.ProjectFilters]) ([]domain.Project, int64, error)
+	ListProjectsByCursor(ctx context.Context, params pagination.ListParams[domain.ProjectFilters]) ([]domain.Project, pagination.CursorPage, error)
+	GetProjectListState(ctx context.Context, filters domain.ProjectFilters) (pagination.ListState, error)
+
+	// SetProjectItem creates or replaces the target and progress of an item in a project.
+	SetProjectItem(ctx context.Context, item *domain.ProjectItem) error
+	DeleteProjectItem(ctx context.Context, projectID, itemID uint64) error
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dubbie/calculator-api/internal/app/pagination"
+	"github.com/dubbie/calculator-api/internal/domain"
+)
+
+// RecipeStore defines the interface for recipe storage operations.
+// Implementations must persist a recipe together with its inputs and outputs atomically.
+type RecipeStore interface {
+	CreateRecipe(ctx context.Context, recipe *domain.Recipe) error
+	GetRecipeByID(ctx context.Context, id uint64) (*domain.Recipe, error)
+	UpdateRecipe(ctx context.Context, recipe *domain.Recipe) error
+	DeleteRecipe(ctx context.Context, id uint64) error
+	ListRecipes(ctx context.Context, params pagination.ListParams[domain.RecipeFilters]) ([]domain.Recipe, int64, error)
+	ListRecipesByCursor(ctx context.Context, params pagination.ListParams[domain.RecipeFilters]) ([]domain.Recipe, pagination.CursorPage, error)
+	GetRecipeListState(ctx context.Context, filters domain.RecipeFilters) (pagination.ListState, error)
+	// ListRecipesByOutputItem returns every recipe producing the item, default recipes first,
+	// then recipes where the item is the primary output, then by ID.
+	ListRecipesByOutputItem(ctx context.Context, itemID uint64) ([]domain.Recipe, error)
+	// ListRecipesCraftableFrom returns every recipe with at least one input whose inputs are
+	// all among the given items, ordered by ID. Quantities are not considered.
+	ListRecipesCraftableFrom(ctx context.Context, itemIDs []uint64) ([]domain.Recipe, error)
+	// ListRecipeEdges returns one edge per (output, input) pair of every recipe.
+	ListRecipeEdges(ctx context.Context) ([]domain.RecipeEdge, error)
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_items;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,7 @@
+-- Creating the composite indexes let InnoDB drop the indexes it had made for the item
+-- foreign keys, so restore those first or the composite ones cannot be dropped (error 1553).
+CREATE INDEX fk_recipe_inputs_item ON recipe_inputs (input_item_id);
+CREATE INDEX fk_recipe_outputs_item ON recipe_outputs (item_id);
+
+DROP INDEX idx_recipe_outputs_item_recipe ON recipe_outputs;
+DROP INDEX idx_recipe_inputs_item_recipe ON recipe_inputs;
@@ -0,0 +1,4 @@
+-- Reverse lookups ("used in", "produced by") filter on the item and return the recipe,
+-- so lead with the item and cover the recipe ID.
+CREATE INDEX idx_recipe_inputs_item_recipe ON recipe_inputs (input_item_id, recipe_id);
+CREATE INDEX idx_recipe_outputs_item_recipe ON recipe_outputs (item_id, recipe_id);
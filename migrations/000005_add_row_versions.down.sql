@@ -0,0 +1,2 @@
+ALTER TABLE crafting_methods DROP COLUMN version;
+ALTER TABLE items DROP COLUMN version;
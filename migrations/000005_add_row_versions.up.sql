@@ -0,0 +1,4 @@
+-- Every update increments version; it backs the ETag of items and crafting methods
+-- and lets writes check that the row is still the one the client read.
+ALTER TABLE items ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER image_url;
+ALTER TABLE crafting_methods ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER description;
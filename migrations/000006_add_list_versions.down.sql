@@ -0,0 +1,4 @@
+ALTER TABLE user_inventories DROP COLUMN version;
+ALTER TABLE project_items DROP COLUMN version;
+ALTER TABLE projects DROP COLUMN version;
+ALTER TABLE recipes DROP COLUMN version;
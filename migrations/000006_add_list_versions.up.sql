@@ -0,0 +1,6 @@
+-- Every update increments version. Lists sum the versions of their rows into their ETag,
+-- which tells apart writes within the same second that updated_at alone cannot.
+ALTER TABLE recipes ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER is_default;
+ALTER TABLE projects ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER description;
+ALTER TABLE project_items ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER produced_quantity;
+ALTER TABLE user_inventories ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER quantity;